@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/megajandrox/go-finance-api/internal/middleweare"
 	"github.com/megajandrox/go-finance-api/pkg/repository"
 	"github.com/megajandrox/go-finance-api/pkg/routerapi"
+	"github.com/megajandrox/go-finance-api/pkg/services"
 )
 
 func main() {
@@ -15,12 +19,65 @@ func main() {
 	db := middleweare.InitializeDatabase()
 	positionRepo := repository.NewPositionRepository(db)
 	assetRepo := repository.NewAssetRepository(db)
+	alertRepo := repository.NewAlertRepository(db)
+	corporateActionRepo := repository.NewCorporateActionRepository(db)
+	incomeRepo := repository.NewIncomeRepository(db)
+	cashRepo := repository.NewCashRepository(db)
+	bondRepo := repository.NewBondRepository(db)
+	uow := repository.NewUnitOfWork(db)
+	ledger := services.NewCashLedger(cashRepo, os.Getenv("CASH_ALLOW_OVERDRAFT") == "true")
+	// Moneda en la que se reportan las valuaciones del portfolio
+	reportingCurrency := os.Getenv("REPORTING_CURRENCY")
+	if reportingCurrency == "" {
+		reportingCurrency = "USD"
+	}
 	v1 := router.Group("/api/v1")
 	{
 		routerapi.QuoteRoutes(v1)
-		routerapi.IndexRoutes(v1)
-		routerapi.AssetRoutes(v1, assetRepo, positionRepo)
+		routerapi.IndexRoutes(v1, positionRepo, corporateActionRepo)
+		routerapi.RuleRoutes(v1, corporateActionRepo)
+		routerapi.AssetRoutes(v1, assetRepo, positionRepo, incomeRepo, bondRepo, corporateActionRepo, uow, ledger)
+		routerapi.AlertRoutes(v1, alertRepo)
+		routerapi.SizingRoutes(v1, positionRepo, corporateActionRepo)
+		routerapi.AnalyticsRoutes(v1, assetRepo, corporateActionRepo)
+		routerapi.OptionRoutes(v1, corporateActionRepo)
+		routerapi.BondRoutes(v1, bondRepo)
+		routerapi.PortfolioRoutes(v1, positionRepo, cashRepo, corporateActionRepo, reportingCurrency)
+		routerapi.CorporateActionRoutes(v1, corporateActionRepo, uow, ledger)
+		routerapi.CashRoutes(v1, cashRepo, ledger)
+	}
+
+	// Evaluar las alertas en segundo plano
+	alertInterval, err := time.ParseDuration(os.Getenv("ALERT_EVALUATION_INTERVAL"))
+	if err != nil {
+		alertInterval = time.Minute
+	}
+	sinks := []services.AlertSink{services.LogSink{}}
+	if webhookURL := os.Getenv("ALERT_WEBHOOK_URL"); webhookURL != "" {
+		sinks = append(sinks, services.NewWebhookSink(webhookURL))
+	}
+	services.NewAlertWorker(alertRepo, corporateActionRepo, alertInterval, sinks...).Start(context.Background())
+
+	// Actualizar los trailing stops de las posiciones abiertas
+	stopInterval, err := time.ParseDuration(os.Getenv("STOP_EVALUATION_INTERVAL"))
+	if err != nil {
+		stopInterval = time.Minute
+	}
+	services.NewStopWorker(positionRepo, corporateActionRepo, stopInterval).Start(context.Background())
+
+	// Aplicar los splits y dividendos al llegar su fecha ex
+	corporateActionInterval, err := time.ParseDuration(os.Getenv("CORPORATE_ACTION_INTERVAL"))
+	if err != nil {
+		corporateActionInterval = time.Hour
+	}
+	services.NewCorporateActionWorker(uow, corporateActionRepo, ledger, corporateActionInterval).Start(context.Background())
+
+	// Devengar las comisiones de prestamo de las posiciones cortas
+	borrowFeeInterval, err := time.ParseDuration(os.Getenv("BORROW_FEE_INTERVAL"))
+	if err != nil {
+		borrowFeeInterval = 24 * time.Hour
 	}
+	services.NewBorrowFeeWorker(positionRepo, borrowFeeInterval).Start(context.Background())
 
 	// Start the HTTP server
 	if err := router.Run(":8080"); err != nil {
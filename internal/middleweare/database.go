@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
 	"github.com/megajandrox/go-finance-api/pkg/models"
@@ -36,9 +37,60 @@ func InitializeDatabase() *gorm.DB {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
+	// Pasar las cantidades enteras a decimales antes de migrar el esquema
+	if err := migrateDecimalQuantities(db); err != nil {
+		log.Fatalf("Failed to migrate quantities to decimal: %v", err)
+	}
+
 	// Migrar el esquema
-	db.AutoMigrate(&models.Asset{}, &models.Position{})
+	db.AutoMigrate(&models.Asset{}, &models.Position{}, &models.Alert{}, &models.AlertEvent{}, &models.CorporateAction{}, &models.Income{}, &models.CashAccount{}, &models.CashTransaction{}, &models.BondIssue{}, &models.BondCashFlow{})
+
+	// Reabrir las posiciones vendidas en parte, que antes quedaban como vendidas
+	if err := reopenPartialSales(db); err != nil {
+		log.Fatalf("Failed to reopen the partially sold positions: %v", err)
+	}
+	if err := markFractionalShares(db); err != nil {
+		log.Fatalf("Failed to mark the positions with fractional shares: %v", err)
+	}
 
 	fmt.Println("Database connected and migrated successfully")
 	return db
 }
+
+// migrateDecimalQuantities converts the integer quantity columns created before the fractional quantities
+// to numeric, the existing values keep the same number
+func migrateDecimalQuantities(db *gorm.DB) error {
+	for _, model := range []interface{}{&models.Position{}, &models.Income{}} {
+		if !db.Migrator().HasColumn(model, "Quantity") {
+			continue
+		}
+		columnTypes, err := db.Migrator().ColumnTypes(model)
+		if err != nil {
+			return err
+		}
+		for _, column := range columnTypes {
+			if column.Name() == "quantity" && !strings.EqualFold(column.DatabaseTypeName(), "numeric") {
+				if err := db.Migrator().AlterColumn(model, "Quantity"); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// reopenPartialSales marks as bought again the positions a partial sale marked as sold,
+// so the corporate actions, income and valuations keep counting the shares left
+func reopenPartialSales(db *gorm.DB) error {
+	return db.Model(&models.Position{}).
+		Where("position_type = ? AND quantity > 0", models.Sold).
+		Update("position_type", models.Bought).Error
+}
+
+// markFractionalShares enables the fractional shares of the positions that already hold a fraction, opened
+// before it was an attribute of the position, so their splits keep the fraction
+func markFractionalShares(db *gorm.DB) error {
+	return db.Model(&models.Position{}).
+		Where("fractional_shares = ? AND quantity <> trunc(quantity)", false).
+		Update("fractional_shares", true).Error
+}
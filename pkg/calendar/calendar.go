@@ -0,0 +1,329 @@
+package calendar
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata" // The sessions are built in the exchange time zone, even on hosts without zoneinfo
+)
+
+// exchangesFile holds the sessions and holidays of the supported exchanges, the holidays and their holidays_to
+// have to be extended every year
+//
+//go:embed exchanges.json
+var exchangesFile []byte
+
+// DefaultExchange is used for the symbols without an exchange suffix, the US listings
+const DefaultExchange = "NYSE"
+
+// UnknownExchange is the code of the calendar returned for the suffixes of exchanges that are not supported
+const UnknownExchange = "UNKNOWN"
+
+/*
+ * Trading sessions of an exchange
+ */
+type Exchange struct {
+	Code         string            `json:"code"`
+	Name         string            `json:"name"`
+	TimeZone     string            `json:"time_zone"`
+	Currency     string            `json:"currency"`      // Currency of the prices (ISO 4217)
+	Open         string            `json:"open"`          // Regular session open, local time (15:04)
+	Close        string            `json:"close"`         // Regular session close, local time (15:04), 24:00 for sessions that last the whole day
+	Weekend      []string          `json:"weekend"`       // Days without session
+	Aliases      []string          `json:"aliases"`       // Exchange codes of the data provider and symbol suffixes
+	PairSuffixes []string          `json:"pair_suffixes"` // Endings of the currency pair symbols it quotes (BTC-USD, EURUSD=X)
+	Holidays     []string          `json:"holidays"`      // Dates without session (2006-01-02)
+	HolidaysFrom string            `json:"holidays_from"` // First date the holidays are listed for, empty when the exchange has none
+	HolidaysTo   string            `json:"holidays_to"`   // Last date the holidays are listed for
+	EarlyCloses  map[string]string `json:"early_closes"`  // Dates with a shortened session and their close
+
+	location     *time.Location
+	holidays     map[string]bool
+	holidaysFrom time.Time
+	holidaysTo   time.Time
+	weekend      map[time.Weekday]bool
+	open         time.Duration
+	close        time.Duration
+}
+
+var exchanges = loadExchanges()
+
+// unknownExchange splits the days in UTC and trades every weekday, its bars are not checked for gaps
+var unknownExchange = newUnknownExchange()
+
+func newUnknownExchange() *Exchange {
+	e := &Exchange{Code: UnknownExchange, Name: "Unknown exchange", TimeZone: "UTC", Open: "00:00", Close: "24:00", Weekend: []string{"Saturday", "Sunday"}}
+	if err := e.init(); err != nil {
+		panic(fmt.Sprintf("invalid calendar of %s: %v", e.Code, err))
+	}
+	return e
+}
+
+func loadExchanges() map[string]*Exchange {
+	var list []*Exchange
+	if err := json.Unmarshal(exchangesFile, &list); err != nil {
+		panic(fmt.Sprintf("invalid exchange calendar: %v", err))
+	}
+	result := map[string]*Exchange{}
+	for _, e := range list {
+		if err := e.init(); err != nil {
+			panic(fmt.Sprintf("invalid calendar of %s: %v", e.Code, err))
+		}
+		result[e.Code] = e
+		for _, alias := range e.Aliases {
+			result[alias] = e
+		}
+	}
+	return result
+}
+
+// parseClock returns the time of the day as a duration since midnight
+func parseClock(value string) (time.Duration, error) {
+	if value == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func (e *Exchange) init() error {
+	var err error
+	if e.location, err = time.LoadLocation(e.TimeZone); err != nil {
+		return err
+	}
+	if e.open, err = parseClock(e.Open); err != nil {
+		return err
+	}
+	if e.close, err = parseClock(e.Close); err != nil {
+		return err
+	}
+	for date, close := range e.EarlyCloses {
+		if _, err := parseClock(close); err != nil {
+			return fmt.Errorf("early close of %s: %w", date, err)
+		}
+	}
+	e.holidays = map[string]bool{}
+	for _, date := range e.Holidays {
+		e.holidays[date] = true
+	}
+	if len(e.Holidays) > 0 {
+		if e.holidaysFrom, err = time.ParseInLocation("2006-01-02", e.HolidaysFrom, e.location); err != nil {
+			return fmt.Errorf("holidays_from: %w", err)
+		}
+		if e.holidaysTo, err = time.ParseInLocation("2006-01-02", e.HolidaysTo, e.location); err != nil {
+			return fmt.Errorf("holidays_to: %w", err)
+		}
+		for _, date := range e.Holidays {
+			if date < e.HolidaysFrom || date > e.HolidaysTo {
+				return fmt.Errorf("holiday %s is out of %s to %s", date, e.HolidaysFrom, e.HolidaysTo)
+			}
+		}
+	}
+	e.weekend = map[time.Weekday]bool{}
+	for _, day := range e.Weekend {
+		found := false
+		for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+			if weekday.String() == day {
+				e.weekend[weekday] = true
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown weekday %q", day)
+		}
+	}
+	return nil
+}
+
+// Get returns the calendar of the exchange by its code or one of its aliases
+func Get(code string) (*Exchange, error) {
+	e, ok := exchanges[strings.ToUpper(code)]
+	if !ok {
+		return nil, fmt.Errorf("unknown exchange %q", code)
+	}
+	return e, nil
+}
+
+// ForSymbol returns the calendar of the exchange of the symbol from its suffix (GGAL.BA) or, for currency
+// pairs, from the ending of the pair (BTC-USD, EURUSD=X). The default exchange when there is no suffix, and
+// the unknown exchange when the suffix is not supported (VOD.L).
+func ForSymbol(symbol string) *Exchange {
+	symbol = strings.ToUpper(symbol)
+	if i := strings.LastIndex(symbol, "."); i >= 0 {
+		if e, err := Get(symbol[i+1:]); err == nil {
+			return e
+		}
+		return unknownExchange
+	}
+	for _, e := range exchanges {
+		for _, suffix := range e.PairSuffixes {
+			if strings.HasSuffix(symbol, suffix) && len(symbol) > len(suffix) {
+				return e
+			}
+		}
+	}
+	return exchanges[DefaultExchange]
+}
+
+// IsKnown reports whether the sessions and holidays of the exchange are supported
+func (e *Exchange) IsKnown() bool {
+	return e != unknownExchange
+}
+
+// HasHolidaysFor reports whether the holidays of the day of date are listed, always for the exchanges without holidays
+func (e *Exchange) HasHolidaysFor(date time.Time) bool {
+	if len(e.Holidays) == 0 {
+		return true
+	}
+	date = e.Date(date)
+	return !date.Before(e.holidaysFrom) && !date.After(e.holidaysTo)
+}
+
+// IsAlwaysOpen reports whether the exchange trades every day of the year
+func (e *Exchange) IsAlwaysOpen() bool {
+	return len(e.weekend) == 0 && len(e.holidays) == 0
+}
+
+// TradingDaysPerYear returns the sessions of a year: 365 for the markets that never close, the usual 252 otherwise
+func (e *Exchange) TradingDaysPerYear() float64 {
+	if e.IsAlwaysOpen() {
+		return 365
+	}
+	return 252
+}
+
+// SessionHours returns the length of the regular session
+func (e *Exchange) SessionHours() float64 {
+	return (e.close - e.open).Hours()
+}
+
+// Location returns the time zone of the exchange
+func (e *Exchange) Location() *time.Location {
+	return e.location
+}
+
+// TradingDay returns the date of the timestamp in the exchange time zone
+func (e *Exchange) TradingDay(timestamp int64) time.Time {
+	return e.Date(time.Unix(timestamp, 0))
+}
+
+// Date returns the midnight of the day of t in the exchange time zone
+func (e *Exchange) Date(t time.Time) time.Time {
+	year, month, day := t.In(e.location).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, e.location)
+}
+
+// IsTradingDay reports whether the exchange has a session on the day of date
+func (e *Exchange) IsTradingDay(date time.Time) bool {
+	date = e.Date(date)
+	return !e.weekend[date.Weekday()] && !e.holidays[date.Format("2006-01-02")]
+}
+
+// Session returns the open and close of the session on the day of date, ok is false when there is no session
+func (e *Exchange) Session(date time.Time) (open time.Time, close time.Time, ok bool) {
+	date = e.Date(date)
+	if !e.IsTradingDay(date) {
+		return time.Time{}, time.Time{}, false
+	}
+	closeOffset := e.close
+	if early, found := e.EarlyCloses[date.Format("2006-01-02")]; found {
+		closeOffset, _ = parseClock(early)
+	}
+	// Adding the clock to the calendar date keeps the local time across daylight saving changes
+	open = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, e.location).Add(e.open)
+	close = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, e.location).Add(closeOffset)
+	return open, close, true
+}
+
+// SessionOpen returns the open of the session of the timestamp, or the timestamp itself when its day has no session
+func (e *Exchange) SessionOpen(timestamp int64) int64 {
+	if open, _, ok := e.Session(e.TradingDay(timestamp)); ok {
+		return open.Unix()
+	}
+	return timestamp
+}
+
+// TradingDays returns the days with a session between start and end, both included
+func (e *Exchange) TradingDays(start time.Time, end time.Time) []time.Time {
+	var days []time.Time
+	for day := e.Date(start); !day.After(end); day = day.AddDate(0, 0, 1) {
+		if e.IsTradingDay(day) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// NextTradingDay returns the first day with a session after the day of date
+func (e *Exchange) NextTradingDay(date time.Time) time.Time {
+	day := e.Date(date).AddDate(0, 0, 1)
+	for !e.IsTradingDay(day) {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// MissingBars returns the opens of the bars the calendar expects between start and end that are not in
+// timestamps. A zero step checks whole sessions, otherwise the bars of 'step' counted from the session open.
+// Bars that did not open yet are not expected, nor the days whose holidays are not listed.
+func (e *Exchange) MissingBars(timestamps []int64, step time.Duration, start time.Time, end time.Time) []time.Time {
+	if !e.IsKnown() {
+		return nil
+	}
+	if now := time.Now(); end.After(now) {
+		end = now
+	}
+	present := map[int64]bool{}
+	for _, ts := range timestamps {
+		if step == 0 {
+			present[e.TradingDay(ts).Unix()] = true
+		} else {
+			present[ts] = true
+		}
+	}
+	var missing []time.Time
+	for _, day := range e.TradingDays(start, end) {
+		if !e.HasHolidaysFor(day) {
+			continue
+		}
+		open, close, _ := e.Session(day)
+		if step == 0 {
+			if !open.After(end) && !present[day.Unix()] {
+				missing = append(missing, open)
+			}
+			continue
+		}
+		for bar := open; bar.Before(close) && !bar.After(end); bar = bar.Add(step) {
+			if !bar.Before(start) && !present[bar.Unix()] {
+				missing = append(missing, bar)
+			}
+		}
+	}
+	return missing
+}
+
+// FormatTimestamp labels the timestamp with the local time and offset of the exchange
+func (e *Exchange) FormatTimestamp(timestamp int64) string {
+	return time.Unix(timestamp, 0).In(e.location).Format(time.RFC3339)
+}
+
+// NextBar returns the open of the bar that follows the one opened at timestamp, skipping to the next session
+// after the close. Steps of a day or longer move to the next trading day at the same local time.
+func (e *Exchange) NextBar(timestamp int64, step time.Duration) int64 {
+	t := time.Unix(timestamp, 0).In(e.location)
+	if step >= 24*time.Hour {
+		next := e.NextTradingDay(t)
+		return time.Date(next.Year(), next.Month(), next.Day(), t.Hour(), t.Minute(), t.Second(), 0, e.location).Unix()
+	}
+	next := t.Add(step)
+	if _, close, ok := e.Session(t); ok && next.Before(close) {
+		return next.Unix()
+	}
+	open, _, _ := e.Session(e.NextTradingDay(t))
+	return open.Unix()
+}
@@ -0,0 +1,195 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func mustGet(t *testing.T, code string) *Exchange {
+	t.Helper()
+	e, err := Get(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func date(e *Exchange, value string) time.Time {
+	d, err := time.ParseInLocation("2006-01-02", value, e.Location())
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestForSymbol(t *testing.T) {
+	tests := []struct {
+		symbol string
+		want   string
+	}{
+		{"AAPL", "NYSE"},
+		{"BRK-B", "NYSE"},
+		{"GGAL.BA", "BYMA"},
+		{"btc-usd", "CRYPTO"},
+		{"ETH-USDT", "CRYPTO"},
+		{"EURUSD=X", "FX"},
+		{"VOD.L", UnknownExchange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.symbol, func(t *testing.T) {
+			if got := ForSymbol(tt.symbol).Code; got != tt.want {
+				t.Errorf("ForSymbol(%q) = %s, want %s", tt.symbol, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsTradingDay(t *testing.T) {
+	nyse, byma, crypto, fx := mustGet(t, "NYSE"), mustGet(t, "BYMA"), mustGet(t, "CRYPTO"), mustGet(t, "FX")
+	tests := []struct {
+		name     string
+		exchange *Exchange
+		day      string
+		want     bool
+	}{
+		{"independence day", nyse, "2024-07-04", false},
+		{"day after independence day", nyse, "2024-07-05", true},
+		{"saturday", nyse, "2024-07-06", false},
+		{"good friday 2027", nyse, "2027-03-26", false},
+		{"carnival", byma, "2024-02-12", false},
+		{"crypto saturday", crypto, "2024-07-06", true},
+		{"crypto christmas", crypto, "2024-12-25", true},
+		{"fx saturday", fx, "2024-07-06", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.exchange.IsTradingDay(date(tt.exchange, tt.day)); got != tt.want {
+				t.Errorf("IsTradingDay(%s) = %v, want %v", tt.day, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSession(t *testing.T) {
+	nyse, crypto := mustGet(t, "NYSE"), mustGet(t, "CRYPTO")
+	tests := []struct {
+		name      string
+		exchange  *Exchange
+		day       string
+		wantOpen  string
+		wantClose string
+	}{
+		{"standard time", nyse, "2024-03-08", "2024-03-08T14:30:00Z", "2024-03-08T21:00:00Z"},
+		// The first session after the daylight saving change opens one hour earlier in UTC
+		{"daylight saving time", nyse, "2024-03-11", "2024-03-11T13:30:00Z", "2024-03-11T20:00:00Z"},
+		{"early close", nyse, "2024-11-29", "2024-11-29T14:30:00Z", "2024-11-29T18:00:00Z"},
+		{"whole day", crypto, "2024-07-06", "2024-07-06T00:00:00Z", "2024-07-07T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			open, close, ok := tt.exchange.Session(date(tt.exchange, tt.day))
+			if !ok {
+				t.Fatalf("no session on %s", tt.day)
+			}
+			if got := open.UTC().Format(time.RFC3339); got != tt.wantOpen {
+				t.Errorf("open = %s, want %s", got, tt.wantOpen)
+			}
+			if got := close.UTC().Format(time.RFC3339); got != tt.wantClose {
+				t.Errorf("close = %s, want %s", got, tt.wantClose)
+			}
+		})
+	}
+}
+
+func TestMissingBars(t *testing.T) {
+	nyse, byma := mustGet(t, "NYSE"), mustGet(t, "BYMA")
+	sessionOpen := func(e *Exchange, day string) int64 {
+		open, _, _ := e.Session(date(e, day))
+		return open.Unix()
+	}
+	tests := []struct {
+		name       string
+		exchange   *Exchange
+		timestamps []int64
+		start, end string
+		want       []string
+	}{
+		{
+			name:       "a session is missing, the holiday is not expected",
+			exchange:   nyse,
+			timestamps: []int64{sessionOpen(nyse, "2024-07-01"), sessionOpen(nyse, "2024-07-02"), sessionOpen(nyse, "2024-07-05")},
+			start:      "2024-07-01", end: "2024-07-05",
+			want: []string{"2024-07-03T13:30:00Z"},
+		},
+		{
+			name:     "the holidays are not listed yet",
+			exchange: byma,
+			start:    "2027-01-04", end: "2027-01-08",
+		},
+		{
+			name:     "unknown exchange",
+			exchange: ForSymbol("VOD.L"),
+			start:    "2024-07-01", end: "2024-07-05",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end := date(tt.exchange, tt.end).AddDate(0, 0, 1).Add(-time.Second)
+			missing := tt.exchange.MissingBars(tt.timestamps, 0, date(tt.exchange, tt.start), end)
+			if len(missing) != len(tt.want) {
+				t.Fatalf("missing = %v, want %v", missing, tt.want)
+			}
+			for i, bar := range missing {
+				if got := bar.UTC().Format(time.RFC3339); got != tt.want[i] {
+					t.Errorf("missing[%d] = %s, want %s", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNextBar(t *testing.T) {
+	nyse := mustGet(t, "NYSE")
+	at := func(value string) int64 {
+		ts, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts.Unix()
+	}
+	tests := []struct {
+		name string
+		from string
+		step time.Duration
+		want string
+	}{
+		{"within the session", "2024-07-01T13:30:00Z", time.Hour, "2024-07-01T14:30:00Z"},
+		// The 12:30 bar is the last one before the early close, the next session after the holiday opens on Friday
+		{"over the holiday", "2024-07-03T16:30:00Z", time.Hour, "2024-07-05T13:30:00Z"},
+		{"daily over the weekend", "2024-07-05T13:30:00Z", 24 * time.Hour, "2024-07-08T13:30:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := time.Unix(nyse.NextBar(at(tt.from), tt.step), 0).UTC().Format(time.RFC3339)
+			if got != tt.want {
+				t.Errorf("NextBar = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTradingDaysPerYear(t *testing.T) {
+	tests := []struct {
+		code string
+		want float64
+	}{
+		{"NYSE", 252},
+		{"FX", 252},
+		{"CRYPTO", 365},
+	}
+	for _, tt := range tests {
+		if got := mustGet(t, tt.code).TradingDaysPerYear(); got != tt.want {
+			t.Errorf("%s: TradingDaysPerYear = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
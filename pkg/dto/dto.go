@@ -1,16 +1,131 @@
 package dto
 
-import "github.com/megajandrox/go-finance-api/pkg/models"
+import (
+	"github.com/megajandrox/go-finance-api/pkg/models"
+	"github.com/shopspring/decimal"
+)
 
 type BuyPosition struct {
 	Symbol     string            `json:"symbol"`      // Financial asset symbol
 	Price      float64           `json:"price"`       // price
-	Quantity   int               `json:"quantity"`    // Quantity of shares/buys
-	MarketType models.MarketType `json:"market_type"` // Market (Equities, ETFs)
+	Quantity   decimal.Decimal   `json:"quantity"`    // Quantity of shares/buys, fractions up to 8 decimal places
+	MarketType models.MarketType `json:"market_type"` // Market (Equities, ETFs, Options, Bonds, Crypto, FX)
+	StopType   models.StopType   `json:"stop_type"`   // Optional stop (fixed, percent trailing, ATR trailing)
+	StopValue  float64           `json:"stop_value"`  // Stop price, trailing percentage or ATR multiple
+	Fee        float64           `json:"fee"`         // Commission debited with the cost
+	Currency   string            `json:"currency"`    // Cash account to debit, the currency of the exchange when empty
+	Option     *OptionContract   `json:"option"`      // Terms of the contract, required when market_type is Option
+	Fractional bool              `json:"fractional"`  // The broker holds fractional shares, required for fractions of a share
+}
+
+type OptionContract struct {
+	Underlying string            `json:"underlying"`  // Symbol of the underlying asset
+	Strike     float64           `json:"strike"`      // Strike price
+	Expiry     string            `json:"expiry"`      // Expiration, ISO date (at the close) or RFC3339
+	OptionType models.OptionType `json:"option_type"` // Call or put
+	Multiplier float64           `json:"multiplier"`  // Shares per contract, 100 when empty
 }
 
 type SellPosition struct {
-	Symbol   string  `json:"symbol"`   // Financial asset symbol
-	Price    float64 `json:"price"`    // price
-	Quantity int     `json:"quantity"` // Quantity of shares/buys
+	Symbol   string          `json:"symbol"`   // Financial asset symbol
+	Price    float64         `json:"price"`    // price
+	Quantity decimal.Decimal `json:"quantity"` // Quantity of shares/buys, fractions up to 8 decimal places
+	Fee      float64         `json:"fee"`      // Commission debited from the proceeds
+}
+
+type ShortPosition struct {
+	Symbol        string            `json:"symbol"`          // Financial asset symbol
+	Price         float64           `json:"price"`           // Short sale price
+	Quantity      decimal.Decimal   `json:"quantity"`        // Quantity of shares borrowed and sold
+	MarketType    models.MarketType `json:"market_type"`     // Market (Equities, ETFs, Options, Bonds, Crypto, FX)
+	BorrowFeeRate float64           `json:"borrow_fee_rate"` // Annual borrow fee, percentage of the value of the shares owed
+	Fee           float64           `json:"fee"`             // Commission debited from the proceeds
+	Currency      string            `json:"currency"`        // Cash account to credit, the currency of the exchange when empty
+	Option        *OptionContract   `json:"option"`          // Terms of the contract written, required when market_type is Option
+	Fractional    bool              `json:"fractional"`      // The broker lends fractional shares, required for fractions of a share
+}
+
+type CoverPosition struct {
+	Price    float64         `json:"price"`    // Price paid to buy back the shares
+	Quantity decimal.Decimal `json:"quantity"` // Quantity of shares to cover
+	Fee      float64         `json:"fee"`      // Commission debited with the cost
+}
+
+type PositionStop struct {
+	StopType models.StopType `json:"stop_type"` // Stop (none, fixed, percent trailing, ATR trailing)
+	Value    float64         `json:"value"`     // Stop price, trailing percentage or ATR multiple
+}
+
+type EvaluateRule struct {
+	Symbol     string `json:"symbol"`     // Financial asset symbol
+	Expression string `json:"expression"` // Rule, e.g. "close > sma(50) and rsi(14) crosses above 30"
+	From       int    `json:"from"`       // Months of history to evaluate
+	Interval   string `json:"interval"`   // Interval of the bars (1h, 1d)
+}
+
+type CreateAlert struct {
+	Symbol     string           `json:"symbol"`     // Financial asset symbol
+	AlertType  models.AlertType `json:"alert_type"` // Condition to watch
+	Level      float64          `json:"level"`      // Price level for the price alerts
+	Expression string           `json:"expression"` // Rule for the expression alerts
+	Interval   string           `json:"interval"`   // Interval of the bars used by indicator alerts (default 1d)
+	From       int              `json:"from"`       // Months of history used by indicator alerts (default 12)
+}
+
+type PositionSizeRequest struct {
+	Symbol           string            `json:"symbol"`            // Financial asset symbol
+	Equity           float64           `json:"equity"`            // Account equity
+	RiskPercent      float64           `json:"risk_percent"`      // Percentage of the equity to risk in the trade
+	EntryPrice       float64           `json:"entry_price"`       // Planned entry, the last quote when empty
+	StopPrice        float64           `json:"stop_price"`        // Planned stop
+	ATRMultiple      float64           `json:"atr_multiple"`      // Stop at entry minus N ATR, when there is no stop price
+	MaxConcentration float64           `json:"max_concentration"` // Optional maximum percentage of the equity in the symbol
+	MarketType       models.MarketType `json:"market_type"`       // Options are sized in whole contracts
+	Option           *OptionContract   `json:"option"`            // Terms of the contract, required when market_type is Option
+	Fractional       bool              `json:"fractional"`        // The broker holds fractional shares, whole shares otherwise
+}
+
+type CorporateAction struct {
+	Symbol          string                     `json:"symbol"`             // Financial asset symbol
+	ActionType      models.CorporateActionType `json:"action_type"`        // Split or cash dividend
+	ExDate          string                     `json:"ex_date"`            // Ex-date (2006-01-02)
+	Ratio           float64                    `json:"ratio"`              // Shares after the split for each share before
+	Amount          float64                    `json:"amount"`             // Cash dividend per share
+	CashInLieuPrice float64                    `json:"cash_in_lieu_price"` // Price after the split paid for the fractions of share, the quote if empty
+}
+
+type ImportCorporateActions struct {
+	Actions []CorporateAction `json:"actions"` // Actions to record, the ones already recorded are skipped
+}
+
+type BondCashFlow struct {
+	PaymentDate  string  `json:"payment_date"` // Payment date (2006-01-02)
+	Coupon       float64 `json:"coupon"`       // Interest per 100 of original face
+	Amortization float64 `json:"amortization"` // Principal per 100 of original face
+}
+
+type BondIssue struct {
+	Symbol    string         `json:"symbol"`     // Financial asset symbol
+	Issuer    string         `json:"issuer"`     // Sovereign or company
+	Currency  string         `json:"currency"`   // Currency of the payments
+	IssueDate string         `json:"issue_date"` // Start of the first coupon period (2006-01-02)
+	CashFlows []BondCashFlow `json:"cash_flows"` // Coupons and amortizations, repaying 100 in total
+}
+
+type RecordIncome struct {
+	IncomeType models.IncomeType `json:"income_type"` // Dividend, coupon or interest
+	Amount     float64           `json:"amount"`      // Total cash, per share times quantity when empty
+	PerShare   float64           `json:"per_share"`   // Cash per share or per bond
+	Quantity   decimal.Decimal   `json:"quantity"`    // Shares or bonds held
+	PaidAt     string            `json:"paid_at"`     // Payment date (2006-01-02), today when empty
+}
+
+type CashMovement struct {
+	Currency    string  `json:"currency"`    // ISO 4217 code
+	Amount      float64 `json:"amount"`      // Positive amount to deposit or withdraw
+	Description string  `json:"description"` // Optional note
+}
+
+type CashAccountSettings struct {
+	AllowOverdraft bool `json:"allow_overdraft"` // Accept buys and fees beyond the balance
 }
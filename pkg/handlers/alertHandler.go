@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/megajandrox/go-finance-api/pkg/dto"
+	"github.com/megajandrox/go-finance-api/pkg/models"
+	"github.com/megajandrox/go-finance-api/pkg/repository"
+)
+
+func GetAllAlerts(repo repository.AlertRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		alerts, err := repo.GetAll()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"alerts": alerts})
+	}
+}
+
+func CreateAlert(repo repository.AlertRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var createAlert dto.CreateAlert
+		if err := c.ShouldBindJSON(&createAlert); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if createAlert.Interval != "" && !IsValidInterval(Interval(createAlert.Interval)) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid field. " + invalidIntervalMessage})
+			return
+		}
+
+		alert, errNew := models.NewAlert(createAlert.Symbol, createAlert.AlertType, createAlert.Level, createAlert.Expression, createAlert.Interval, createAlert.From)
+		if errNew != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errNew.Error()})
+			return
+		}
+		if err := repo.Create(alert); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Alert created successfully", "alert": alert})
+	}
+}
+
+func DeleteAlert(repo repository.AlertRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+			return
+		}
+
+		if _, err := repo.GetByID(uint(id)); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err := repo.Delete(uint(id)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Alert deleted successfully"})
+	}
+}
+
+// GetAlertEvents lists the triggered events, filtered by 'alert_id' and 'symbol'
+func GetAlertEvents(repo repository.AlertRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var alertID, limit int
+		var err error
+		if alertIDParam := c.Query("alert_id"); alertIDParam != "" {
+			alertID, err = strconv.Atoi(alertIDParam)
+			if err != nil || alertID < 1 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameter. 'alert_id' must be a positive integer."})
+				return
+			}
+		}
+		limit = 100
+		if limitParam := c.Query("limit"); limitParam != "" {
+			limit, err = strconv.Atoi(limitParam)
+			if err != nil || limit < 1 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameter. 'limit' must be a positive integer."})
+				return
+			}
+		}
+
+		events, err := repo.GetEvents(uint(alertID), c.Query("symbol"), limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"events": events})
+	}
+}
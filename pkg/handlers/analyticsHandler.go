@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/megajandrox/go-finance-api/pkg/repository"
+	"github.com/megajandrox/go-finance-api/pkg/services"
+)
+
+// parseAnalyticsParams validates the 'from' and 'interval' query parameters shared by the analytics endpoints
+func parseAnalyticsParams(c *gin.Context) (int, string, bool) {
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil || from < 2 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameter. 'from' must be an integer greater than 1."})
+		return 0, "", false
+	}
+	interval := c.DefaultQuery("interval", string(OneDay))
+	if !IsValidInterval(Interval(interval)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameter. " + invalidIntervalMessage})
+		return 0, "", false
+	}
+	return from, interval, true
+}
+
+// GetRiskAnalytics returns the historical volatility of the symbol and its beta and correlation with 'benchmark'
+func GetRiskAnalytics(actions repository.CorporateActionRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		symbol := c.Param("symbol")
+		from, interval, ok := parseAnalyticsParams(c)
+		if !ok {
+			return
+		}
+		analytics, err := services.FindRiskAnalyticsBySymbol(services.NewSplitAdjuster(actions), symbol, c.DefaultQuery("benchmark", "SPY"), from, interval)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, analytics)
+	}
+}
+
+// GetCorrelationMatrix returns the correlation of the returns across every Asset
+func GetCorrelationMatrix(repo repository.AssetRepository, actions repository.CorporateActionRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		from, interval, ok := parseAnalyticsParams(c)
+		if !ok {
+			return
+		}
+		assets, err := repo.GetAll()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		symbols := make([]string, 0, len(assets))
+		for _, asset := range assets {
+			symbols = append(symbols, asset.Symbol)
+		}
+		c.JSON(http.StatusOK, services.FindCorrelationMatrix(services.NewSplitAdjuster(actions), symbols, from, interval))
+	}
+}
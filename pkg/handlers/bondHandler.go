@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/megajandrox/go-finance-api/pkg/calendar"
+	"github.com/megajandrox/go-finance-api/pkg/dto"
+	"github.com/megajandrox/go-finance-api/pkg/models"
+	"github.com/megajandrox/go-finance-api/pkg/repository"
+	"github.com/megajandrox/go-finance-api/pkg/services"
+)
+
+// parseSettlement reads the 'settlement' query parameter, today in the exchange of the symbol when empty
+func parseSettlement(c *gin.Context, symbol string) (time.Time, bool) {
+	exchange := calendar.ForSymbol(symbol)
+	value := c.Query("settlement")
+	if value == "" {
+		return exchange.Date(time.Now()), true
+	}
+	settlement, err := parseDateTime(value, exchange)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameter. 'settlement' must be a date (2006-01-02)."})
+		return time.Time{}, false
+	}
+	return settlement, true
+}
+
+func GetBonds(repo repository.BondRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bonds, err := repo.GetAll()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"bonds": bonds})
+	}
+}
+
+func GetBond(repo repository.BondRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bond, err := repo.GetBySymbol(strings.ToUpper(c.Param("symbol")))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"bond": bond})
+	}
+}
+
+// CreateBond records the terms and the payment schedule of a bond
+func CreateBond(repo repository.BondRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var bondIssue dto.BondIssue
+		if err := c.ShouldBindJSON(&bondIssue); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		exchange := calendar.ForSymbol(bondIssue.Symbol)
+		issueDate, err := parseDateTime(bondIssue.IssueDate, exchange)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid field. 'issue_date' must be a date (2006-01-02)."})
+			return
+		}
+		cashFlows := make([]models.BondCashFlow, 0, len(bondIssue.CashFlows))
+		for i, f := range bondIssue.CashFlows {
+			paymentDate, err := parseDateTime(f.PaymentDate, exchange)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Cash flow %d: 'payment_date' must be a date (2006-01-02).", i)})
+				return
+			}
+			cashFlows = append(cashFlows, models.BondCashFlow{PaymentDate: paymentDate, Coupon: f.Coupon, Amortization: f.Amortization})
+		}
+		currency := bondIssue.Currency
+		if currency == "" {
+			currency = exchange.Currency
+		}
+		if currency == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid field. 'currency' is required for the symbols of an unknown exchange."})
+			return
+		}
+		bond, err := models.NewBondIssue(bondIssue.Symbol, bondIssue.Issuer, currency, issueDate, cashFlows)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := repo.Create(bond); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Bond created successfully", "bond": bond})
+	}
+}
+
+// GetBondAnalytics returns the yield to maturity, current yield, duration and convexity of the bond at 'price',
+// its quote when empty
+func GetBondAnalytics(repo repository.BondRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bond, err := repo.GetBySymbol(strings.ToUpper(c.Param("symbol")))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		price, err := strconv.ParseFloat(c.DefaultQuery("price", "0"), 64)
+		if err != nil || price < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameter. 'price' must be a non negative number."})
+			return
+		}
+		settlement, ok := parseSettlement(c, bond.Symbol)
+		if !ok {
+			return
+		}
+
+		analytics, err := services.FindBondAnalytics(bond, price, settlement)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, analytics)
+	}
+}
+
+// GetBondPositionValue returns the value of a bond position at the quote, with its accrued interest
+func GetBondPositionValue(repo repository.PositionRepository, bondRepo repository.BondRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("idPosition")
+		idInt, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+			return
+		}
+
+		position, errGetByID := repo.GetByID(uint(idInt))
+		if errGetByID != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": errGetByID.Error()})
+			return
+		}
+		if position.MarketType != models.Bond {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Bond valuation is only available for bond positions."})
+			return
+		}
+		bond, err := bondRepo.GetBySymbol(strings.ToUpper(position.Symbol))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Bond terms not found for " + position.Symbol})
+			return
+		}
+		settlement, ok := parseSettlement(c, bond.Symbol)
+		if !ok {
+			return
+		}
+
+		value, err := services.FindBondPositionValue(bond, position, settlement)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, value)
+	}
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/megajandrox/go-finance-api/pkg/dto"
+	"github.com/megajandrox/go-finance-api/pkg/models"
+	"github.com/megajandrox/go-finance-api/pkg/repository"
+	"github.com/megajandrox/go-finance-api/pkg/services"
+	"gorm.io/gorm"
+)
+
+func GetCashAccounts(repo repository.CashRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accounts, err := repo.GetAll()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"accounts": accounts})
+	}
+}
+
+// recordCashMovement handles the deposits and withdrawals
+func recordCashMovement(ledger *services.CashLedger, transactionType models.CashTransactionType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var movement dto.CashMovement
+		if err := c.ShouldBindJSON(&movement); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if len(movement.Currency) != 3 || movement.Amount <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Fields 'currency' (3 letter code) and a positive 'amount' are required."})
+			return
+		}
+		description := movement.Description
+		if description == "" {
+			description = transactionType.String()
+		}
+		transaction, err := ledger.Record(movement.Currency, transactionType, movement.Amount, description, nil, nil)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, models.ErrInsufficientCash) {
+				status = http.StatusBadRequest
+			}
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": transactionType.String() + " recorded successfully", "transaction": transaction})
+	}
+}
+
+func DepositCash(ledger *services.CashLedger) gin.HandlerFunc {
+	return recordCashMovement(ledger, models.Deposit)
+}
+
+func WithdrawCash(ledger *services.CashLedger) gin.HandlerFunc {
+	return recordCashMovement(ledger, models.Withdrawal)
+}
+
+// getCashAccount loads the account of the ':currency' parameter, answering 404 when it does not exist
+func getCashAccount(c *gin.Context, repo repository.CashRepository) (*models.CashAccount, bool) {
+	account, err := repo.GetByCurrency(strings.ToUpper(c.Param("currency")))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return account, true
+}
+
+func UpdateCashAccount(repo repository.CashRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var settings dto.CashAccountSettings
+		if err := c.ShouldBindJSON(&settings); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		account, ok := getCashAccount(c, repo)
+		if !ok {
+			return
+		}
+		account.AllowOverdraft = settings.AllowOverdraft
+		if err := repo.UpdateSettings(account); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Cash account updated successfully", "account": account})
+	}
+}
+
+// GetCashHistory returns the movements of the account with the balance after each one, between 'from' and 'to'
+func GetCashHistory(repo repository.CashRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		account, ok := getCashAccount(c, repo)
+		if !ok {
+			return
+		}
+		var from, to time.Time
+		var err error
+		if fromParam := c.Query("from"); fromParam != "" {
+			if from, err = time.ParseInLocation("2006-01-02", fromParam, time.Local); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameter. 'from' must be a date (2006-01-02)."})
+				return
+			}
+		}
+		if toParam := c.Query("to"); toParam != "" {
+			if to, err = time.ParseInLocation("2006-01-02", toParam, time.Local); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameter. 'to' must be a date (2006-01-02)."})
+				return
+			}
+			to = to.AddDate(0, 0, 1).Add(-time.Second)
+		}
+		transactions, err := repo.GetTransactions(account.ID, from, to)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"account": account, "history": transactions})
+	}
+}
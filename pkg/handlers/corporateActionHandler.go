@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/megajandrox/go-finance-api/pkg/calendar"
+	"github.com/megajandrox/go-finance-api/pkg/dto"
+	"github.com/megajandrox/go-finance-api/pkg/models"
+	"github.com/megajandrox/go-finance-api/pkg/repository"
+	"github.com/megajandrox/go-finance-api/pkg/services"
+)
+
+func GetCorporateActions(repo repository.CorporateActionRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var actions []models.CorporateAction
+		var err error
+		if symbol := c.Query("symbol"); symbol != "" {
+			actions, err = repo.GetBySymbol(symbol)
+		} else {
+			actions, err = repo.GetAll()
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"corporate_actions": actions})
+	}
+}
+
+// ImportCorporateActions records the splits and dividends and applies the ones whose ex-date was reached
+func ImportCorporateActions(repo repository.CorporateActionRepository, uow repository.UnitOfWork, ledger *services.CashLedger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var importActions dto.ImportCorporateActions
+		if err := c.ShouldBindJSON(&importActions); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		actions := make([]*models.CorporateAction, 0, len(importActions.Actions))
+		for i, a := range importActions.Actions {
+			exDate, err := parseDateTime(a.ExDate, calendar.ForSymbol(a.Symbol))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Action %d: 'ex_date' must be a date (2006-01-02).", i)})
+				return
+			}
+			action, err := models.NewCorporateAction(a.Symbol, a.ActionType, exDate, a.Ratio, a.Amount, a.CashInLieuPrice)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Action %d: %v", i, err)})
+				return
+			}
+			actions = append(actions, action)
+		}
+
+		created, skipped, err := services.ImportCorporateActions(repo, actions)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		applied, err := services.ApplyPendingCorporateActions(uow, repo, ledger)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":           "Corporate actions imported successfully",
+			"corporate_actions": created,
+			"skipped":           skipped,
+			"applied":           applied,
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/megajandrox/go-finance-api/pkg/calendar"
+	"github.com/megajandrox/go-finance-api/pkg/dto"
+	"github.com/megajandrox/go-finance-api/pkg/models"
+	"github.com/megajandrox/go-finance-api/pkg/repository"
+	"github.com/megajandrox/go-finance-api/pkg/services"
+)
+
+// GetAssetIncome lists the cash received by the Asset with its total return and yield on cost
+func GetAssetIncome(repo repository.AssetRepository, positionRepo repository.PositionRepository, incomeRepo repository.IncomeRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+			return
+		}
+		asset, err := repo.GetByID(uint(id))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		positions, err := positionRepo.GetByAsset(asset.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		incomes, err := incomeRepo.GetByAsset(asset.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Without a quote the open positions are valued at cost
+		var marketPrice float64
+		if quote, err := services.FindQuote(asset.Symbol); err == nil {
+			marketPrice = quote.RegularMarketPrice
+		}
+		summary := models.SummarizeIncome(asset.Symbol, positions, incomes, marketPrice, time.Now())
+
+		c.JSON(http.StatusOK, gin.H{"income": incomes, "summary": summary})
+	}
+}
+
+// CreateAssetIncome records a dividend, coupon or interest payment of the Asset
+func CreateAssetIncome(repo repository.AssetRepository, positionRepo repository.PositionRepository, bondRepo repository.BondRepository, uow repository.UnitOfWork, ledger *services.CashLedger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var recordIncome dto.RecordIncome
+		if err := c.ShouldBindJSON(&recordIncome); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+			return
+		}
+		asset, err := repo.GetByID(uint(id))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		var paidAt time.Time
+		if recordIncome.PaidAt != "" {
+			paidAt, err = parseDateTime(recordIncome.PaidAt, calendar.ForSymbol(asset.Symbol))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid field. 'paid_at' must be a date (2006-01-02) or an RFC 3339 timestamp."})
+				return
+			}
+		}
+		income, err := models.NewIncome(asset.ID, asset.Symbol, recordIncome.IncomeType, recordIncome.Amount, recordIncome.PerShare, recordIncome.Quantity, paidAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		currency, err := services.IncomeCurrency(bondRepo, positionRepo, asset.ID, asset.Symbol)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		err = uow.Transaction(func(repos repository.Repositories) error {
+			if err := repos.Incomes.Create(income); err != nil {
+				return err
+			}
+			return ledger.WithRepository(repos.Cash).RecordIncome(income, currency)
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Income recorded successfully", "income": income})
+	}
+}
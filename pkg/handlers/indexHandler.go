@@ -1,95 +1,262 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/megajandrox/go-finance-api/pkg/calendar"
+	"github.com/megajandrox/go-finance-api/pkg/models"
+	"github.com/megajandrox/go-finance-api/pkg/repository"
 	"github.com/megajandrox/go-finance-api/pkg/services"
 )
 
 // QuoteResponse represents the JSON structure for the quote response
 type IndexResponse struct {
-	Symbol                       string `json:"symbol"`
-	SMAResult                    string `json:"sma_result"`
-	SMAAnalysis                  string `json:"sma_analysis"`
-	EMAResult                    string `json:"ema_result"`
-	EMAAnalysis                  string `json:"ema_analysis"`
-	MACDResult                   string `json:"macd_result"`
-	MACDAnalysis                 string `json:"macd_analysis"`
-	RSIResult                    string `json:"rsi_result"`
-	RSIAnalysis                  string `json:"rsi_analysis"`
-	StochasticOscillatorResult   string `json:"stochastic_oscillator_result"`
-	StochasticOscillatorAnalysis string `json:"stochastic_oscillator_analysis"`
-	VolumeAnalysis               string `json:"volume_analysis"`
-	OBVAnalysis                  string `json:"obv_analysis"`
-	RVOLAnalysis                 string `json:"rvol_analysis"`
-	ADXResult                    string `json:"adx_result"`
-	ADXAnalysis                  string `json:"adx_analysis"`
-	MomentumResult               string `json:"momentum_result"`
-	MomentumAnalysis             string `json:"momentum_analysis"`
-	CCIResult                    string `json:"cci_result"`
-	CCIAnalysis                  string `json:"cci_analysis"`
+	Symbol                       string                      `json:"symbol"`
+	Exchange                     string                      `json:"exchange"`
+	Start                        string                      `json:"start"`
+	End                          string                      `json:"end"`
+	SMAResult                    string                      `json:"sma_result"`
+	SMAAnalysis                  string                      `json:"sma_analysis"`
+	EMAResult                    string                      `json:"ema_result"`
+	EMAAnalysis                  string                      `json:"ema_analysis"`
+	MACDResult                   string                      `json:"macd_result"`
+	MACDAnalysis                 string                      `json:"macd_analysis"`
+	RSIResult                    string                      `json:"rsi_result"`
+	RSIAnalysis                  string                      `json:"rsi_analysis"`
+	StochasticOscillatorResult   string                      `json:"stochastic_oscillator_result"`
+	StochasticOscillatorAnalysis string                      `json:"stochastic_oscillator_analysis"`
+	VolumeAnalysis               string                      `json:"volume_analysis"`
+	OBVAnalysis                  string                      `json:"obv_analysis"`
+	RVOLAnalysis                 string                      `json:"rvol_analysis"`
+	ADXResult                    string                      `json:"adx_result"`
+	ADXAnalysis                  string                      `json:"adx_analysis"`
+	MomentumResult               string                      `json:"momentum_result"`
+	MomentumAnalysis             string                      `json:"momentum_analysis"`
+	CCIResult                    string                      `json:"cci_result"`
+	CCIAnalysis                  string                      `json:"cci_analysis"`
+	SupportResistanceResult      string                      `json:"support_resistance_result"`
+	SupportResistanceAnalysis    string                      `json:"support_resistance_analysis"`
+	NearestSupport               *float64                    `json:"nearest_support,omitempty"`
+	NearestResistance            *float64                    `json:"nearest_resistance,omitempty"`
+	FibonacciResult              string                      `json:"fibonacci_result"`
+	FibonacciAnalysis            string                      `json:"fibonacci_analysis"`
+	FibonacciLevels              []models.FibonacciLevel     `json:"fibonacci_levels,omitempty"`
+	DivergenceResult             string                      `json:"divergence_result"`
+	DivergenceAnalysis           string                      `json:"divergence_analysis"`
+	Divergences                  []models.DivergenceSignal   `json:"divergences,omitempty"`
+	CandlestickResult            string                      `json:"candlestick_result"`
+	CandlestickAnalysis          string                      `json:"candlestick_analysis"`
+	CandlestickPatterns          []models.CandlestickPattern `json:"candlestick_patterns,omitempty"`
+	IchimokuResult               string                      `json:"ichimoku_result"`
+	IchimokuAnalysis             string                      `json:"ichimoku_analysis"`
+	IchimokuSeries               []models.IchimokuPoint      `json:"ichimoku_series,omitempty"`
+	VWAPResult                   string                      `json:"vwap_result"`
+	VWAPAnalysis                 string                      `json:"vwap_analysis"`
+	MFIResult                    string                      `json:"mfi_result"`
+	MFIAnalysis                  string                      `json:"mfi_analysis"`
+	WilliamsRResult              string                      `json:"williams_r_result"`
+	WilliamsRAnalysis            string                      `json:"williams_r_analysis"`
+	CMFResult                    string                      `json:"cmf_result"`
+	CMFAnalysis                  string                      `json:"cmf_analysis"`
+	SupertrendResult             string                      `json:"supertrend_result"`
+	SupertrendAnalysis           string                      `json:"supertrend_analysis"`
+	AroonResult                  string                      `json:"aroon_result"`
+	AroonAnalysis                string                      `json:"aroon_analysis"`
+	TRIXResult                   string                      `json:"trix_result"`
+	TRIXAnalysis                 string                      `json:"trix_analysis"`
+	MissingBars                  []string                    `json:"missing_bars,omitempty"`
+	Series                       map[string][]float64        `json:"series,omitempty"`
 }
 
-// getQuote handles the retrieval of stock quotes
-func GetIndex(c *gin.Context) {
-	symbol := c.Param("symbol")
-	fromParam := c.Query("from")
-	intervalParam := c.Query("interval")
-	if fromParam == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Query parameter 'from' is required.",
-		})
-		return
+// trendResult hides the trend of the analyzers that did not run or failed
+func trendResult(trendType models.TrendType, result string) string {
+	if result == "" {
+		return ""
 	}
+	return trendType.String()
+}
 
-	// Convert the query parameter `from` to an integer
-	from, err := strconv.Atoi(fromParam)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid query parameter. 'from' must be an integer.",
-		})
-		return
-	}
+// getQuote handles the retrieval of stock quotes
+func GetIndex(actions repository.CorporateActionRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		symbol := c.Param("symbol")
+		exchange := calendar.ForSymbol(symbol)
+		intervalParam := c.Query("interval")
+		if !IsValidInterval(Interval(intervalParam)) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid query parameter. " + invalidIntervalMessage,
+			})
+			return
+		}
 
-	if from < 2 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid query parameter. 'from' must be greater than 1.",
-		})
-		return
-	}
+		// The window ends at 'end', or at 'as_of' to see what the indicators said on a past date
+		now := time.Now()
+		end := now
+		endParam := c.Query("end")
+		if asOfParam := c.Query("as_of"); asOfParam != "" {
+			if endParam != "" {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "Query parameters 'end' and 'as_of' cannot be used together.",
+				})
+				return
+			}
+			endParam = asOfParam
+		}
+		if endParam != "" {
+			parsed, err := parseEndDateTime(endParam, exchange)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "Invalid query parameter. 'end' and 'as_of' must be a date (2006-01-02) or an RFC 3339 timestamp.",
+				})
+				return
+			}
+			if parsed.Before(now) {
+				end = parsed
+			}
+		}
 
-	if !IsValidInterval(Interval(intervalParam)) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid query parameter. 'Interval' must be at least 1h or 1d.",
-		})
-		return
-	}
+		// The window starts at 'start', or 'from' months before its end
+		var start time.Time
+		if startParam := c.Query("start"); startParam != "" {
+			parsed, err := parseDateTime(startParam, exchange)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "Invalid query parameter. 'start' must be a date (2006-01-02) or an RFC 3339 timestamp.",
+				})
+				return
+			}
+			start = parsed
+		} else {
+			fromParam := c.Query("from")
+			if fromParam == "" {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "Query parameter 'from' or 'start' is required.",
+				})
+				return
+			}
 
-	indexes, _ := services.FindIndexesBySymbol(symbol, from, intervalParam)
-	response := IndexResponse{
-		Symbol:                       symbol,
-		SMAResult:                    indexes.SMA.TrendType.String(),
-		SMAAnalysis:                  indexes.SMA.Result,
-		EMAResult:                    indexes.EMA.TrendType.String(),
-		EMAAnalysis:                  indexes.EMA.Result,
-		MACDResult:                   indexes.MACD.TrendType.String(),
-		MACDAnalysis:                 indexes.MACD.Result,
-		RSIResult:                    indexes.RSI.TrendType.String(),
-		RSIAnalysis:                  indexes.RSI.Result,
-		StochasticOscillatorResult:   indexes.Stochastic.TrendType.String(),
-		StochasticOscillatorAnalysis: indexes.Stochastic.Result,
-		VolumeAnalysis:               indexes.Volume.Result,
-		OBVAnalysis:                  indexes.OBV.Result,
-		RVOLAnalysis:                 indexes.RVOL.Result,
-		ADXResult:                    indexes.ADX.TrendType.String(),
-		ADXAnalysis:                  indexes.ADX.Result,
-		MomentumResult:               indexes.Momentum.TrendType.String(),
-		MomentumAnalysis:             indexes.Momentum.Result,
-		CCIResult:                    indexes.CCI.TrendType.String(),
-		CCIAnalysis:                  indexes.CCI.Result,
-	}
+			// Convert the query parameter `from` to an integer
+			from, err := strconv.Atoi(fromParam)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "Invalid query parameter. 'from' must be an integer.",
+				})
+				return
+			}
+
+			if from < 2 {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "Invalid query parameter. 'from' must be greater than 1.",
+				})
+				return
+			}
+			start, _ = services.MonthRange(from, end)
+		}
+
+		if err := ValidateRange(Interval(intervalParam), start, end); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid date range. " + err.Error(),
+			})
+			return
+		}
 
-	c.JSON(http.StatusOK, response)
+		// Optional comma separated list of analyzers, every analyzer runs when it is empty
+		var analyzers []string
+		if analyzersParam := c.Query("analyzers"); analyzersParam != "" {
+			analyzers = strings.Split(analyzersParam, ",")
+		}
+
+		indexes, err := services.FindSelectedIndexesBetween(services.NewSplitAdjuster(actions), symbol, start, end, intervalParam, analyzers)
+		if errors.Is(err, services.ErrUnknownAnalyzer) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":     err.Error(),
+				"analyzers": services.AnalyzerNames(),
+			})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		response := IndexResponse{
+			Symbol:                       symbol,
+			Exchange:                     exchange.Code,
+			Start:                        exchange.FormatTimestamp(start.Unix()),
+			End:                          exchange.FormatTimestamp(end.Unix()),
+			SMAResult:                    trendResult(indexes.SMA.TrendType, indexes.SMA.Result),
+			SMAAnalysis:                  indexes.SMA.Result,
+			EMAResult:                    trendResult(indexes.EMA.TrendType, indexes.EMA.Result),
+			EMAAnalysis:                  indexes.EMA.Result,
+			MACDResult:                   trendResult(indexes.MACD.TrendType, indexes.MACD.Result),
+			MACDAnalysis:                 indexes.MACD.Result,
+			RSIResult:                    trendResult(indexes.RSI.TrendType, indexes.RSI.Result),
+			RSIAnalysis:                  indexes.RSI.Result,
+			StochasticOscillatorResult:   trendResult(indexes.Stochastic.TrendType, indexes.Stochastic.Result),
+			StochasticOscillatorAnalysis: indexes.Stochastic.Result,
+			VolumeAnalysis:               indexes.Volume.Result,
+			OBVAnalysis:                  indexes.OBV.Result,
+			RVOLAnalysis:                 indexes.RVOL.Result,
+			ADXResult:                    trendResult(indexes.ADX.TrendType, indexes.ADX.Result),
+			ADXAnalysis:                  indexes.ADX.Result,
+			MomentumResult:               trendResult(indexes.Momentum.TrendType, indexes.Momentum.Result),
+			MomentumAnalysis:             indexes.Momentum.Result,
+			CCIResult:                    trendResult(indexes.CCI.TrendType, indexes.CCI.Result),
+			CCIAnalysis:                  indexes.CCI.Result,
+			SupportResistanceResult:      trendResult(indexes.SupportResistance.TrendType, indexes.SupportResistance.Result),
+			SupportResistanceAnalysis:    indexes.SupportResistance.Result,
+			FibonacciResult:              trendResult(indexes.Fibonacci.TrendType, indexes.Fibonacci.Result),
+			FibonacciAnalysis:            indexes.Fibonacci.Result,
+			FibonacciLevels:              indexes.Fibonacci.Levels,
+			DivergenceResult:             trendResult(indexes.Divergence.TrendType, indexes.Divergence.Result),
+			DivergenceAnalysis:           indexes.Divergence.Result,
+			Divergences:                  indexes.Divergence.Divergences,
+			CandlestickResult:            trendResult(indexes.Candlestick.TrendType, indexes.Candlestick.Result),
+			CandlestickAnalysis:          indexes.Candlestick.Result,
+			CandlestickPatterns:          indexes.Candlestick.Patterns,
+			IchimokuResult:               trendResult(indexes.Ichimoku.TrendType, indexes.Ichimoku.Result),
+			IchimokuAnalysis:             indexes.Ichimoku.Result,
+			VWAPResult:                   trendResult(indexes.VWAP.TrendType, indexes.VWAP.Result),
+			VWAPAnalysis:                 indexes.VWAP.Result,
+			MFIResult:                    trendResult(indexes.MFI.TrendType, indexes.MFI.Result),
+			MFIAnalysis:                  indexes.MFI.Result,
+			WilliamsRResult:              trendResult(indexes.WilliamsR.TrendType, indexes.WilliamsR.Result),
+			WilliamsRAnalysis:            indexes.WilliamsR.Result,
+			CMFResult:                    trendResult(indexes.CMF.TrendType, indexes.CMF.Result),
+			CMFAnalysis:                  indexes.CMF.Result,
+			SupertrendResult:             trendResult(indexes.Supertrend.TrendType, indexes.Supertrend.Result),
+			SupertrendAnalysis:           indexes.Supertrend.Result,
+			AroonResult:                  trendResult(indexes.Aroon.TrendType, indexes.Aroon.Result),
+			AroonAnalysis:                indexes.Aroon.Result,
+			TRIXResult:                   trendResult(indexes.TRIX.TrendType, indexes.TRIX.Result),
+			TRIXAnalysis:                 indexes.TRIX.Result,
+		}
+		// The series are only included on request, they have one point per bar
+		if c.Query("series") == "true" {
+			response.IchimokuSeries = indexes.Ichimoku.Series
+			response.Series = map[string][]float64{
+				"supertrend":       indexes.Supertrend.Supertrend,
+				"aroon_up":         indexes.Aroon.AroonUp,
+				"aroon_down":       indexes.Aroon.AroonDown,
+				"aroon_oscillator": indexes.Aroon.Oscillator,
+				"trix":             indexes.TRIX.TRIXArray,
+				"trix_signal":      indexes.TRIX.TRIXSignal,
+			}
+		}
+		for _, bar := range indexes.MissingBars {
+			response.MissingBars = append(response.MissingBars, exchange.FormatTimestamp(bar.Unix()))
+		}
+		if indexes.SupportResistance.NearestSupport != nil {
+			response.NearestSupport = &indexes.SupportResistance.NearestSupport.Price
+		}
+		if indexes.SupportResistance.NearestResistance != nil {
+			response.NearestResistance = &indexes.SupportResistance.NearestResistance.Price
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
 }
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/megajandrox/go-finance-api/pkg/calendar"
+	"github.com/megajandrox/go-finance-api/pkg/dto"
+	"github.com/megajandrox/go-finance-api/pkg/models"
+	"github.com/megajandrox/go-finance-api/pkg/repository"
+	"github.com/megajandrox/go-finance-api/pkg/services"
+)
+
+// optionContract validates the contract of a new position, nil when the position is not on an option
+func optionContract(marketType models.MarketType, terms *dto.OptionContract) (*models.OptionContract, error) {
+	if marketType != models.Option {
+		return nil, nil
+	}
+	if terms == nil {
+		return nil, errors.New("Invalid field. 'option' is required for option positions.")
+	}
+	expiry, err := parseDateTime(terms.Expiry, calendar.ForSymbol(terms.Underlying))
+	if err != nil {
+		return nil, errors.New("Invalid field. 'expiry' must be an ISO date or RFC3339.")
+	}
+	return models.NewOptionContract(terms.Underlying, terms.Strike, expiry, terms.OptionType, terms.Multiplier)
+}
+
+// parseRate reads the 'rate' query parameter, an annual percentage, as a decimal
+func parseRate(c *gin.Context) (float64, bool) {
+	rate, err := strconv.ParseFloat(c.DefaultQuery("rate", "0"), 64)
+	if err != nil || rate < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameter. 'rate' must be a non negative percentage."})
+		return 0, false
+	}
+	return rate / 100, true
+}
+
+// GetOptionPricing returns the Black-Scholes value and greeks of the contract described by the query and,
+// with 'price', its implied volatility
+func GetOptionPricing(actions repository.CorporateActionRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		underlying := c.Query("underlying")
+		strike, err := strconv.ParseFloat(c.Query("strike"), 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameter. 'strike' must be a number."})
+			return
+		}
+		optionType, err := models.ParseOptionType(c.DefaultQuery("type", "call"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameter. " + err.Error()})
+			return
+		}
+		multiplier, err := strconv.ParseFloat(c.DefaultQuery("multiplier", "0"), 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameter. 'multiplier' must be a number."})
+			return
+		}
+		price, err := strconv.ParseFloat(c.DefaultQuery("price", "0"), 64)
+		if err != nil || price < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameter. 'price' must be a non negative number."})
+			return
+		}
+		rate, ok := parseRate(c)
+		if !ok {
+			return
+		}
+		contract, err := optionContract(models.Option, &dto.OptionContract{
+			Underlying: underlying,
+			Strike:     strike,
+			Expiry:     c.Query("expiry"),
+			OptionType: optionType,
+			Multiplier: multiplier,
+		})
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		valuation, err := services.FindOptionValuation(services.NewSplitAdjuster(actions), *contract, rate, price)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, valuation)
+	}
+}
+
+// GetPositionGreeks returns the value and greeks of an option position at the quotes of the contract and its underlying
+func GetPositionGreeks(repo repository.PositionRepository, actions repository.CorporateActionRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("idPosition")
+		idInt, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+			return
+		}
+		rate, ok := parseRate(c)
+		if !ok {
+			return
+		}
+
+		position, errGetByID := repo.GetByID(uint(idInt))
+		if errGetByID != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": errGetByID.Error()})
+			return
+		}
+		if !position.IsOption() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Greeks are only available for option positions."})
+			return
+		}
+		valuation, err := services.FindPositionValuation(services.NewSplitAdjuster(actions), position, rate)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, valuation)
+	}
+}
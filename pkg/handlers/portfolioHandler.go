@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/megajandrox/go-finance-api/pkg/repository"
+	"github.com/megajandrox/go-finance-api/pkg/services"
+)
+
+// reportingCurrency returns the 'currency' query parameter, the configured reporting currency when empty
+func reportingCurrency(c *gin.Context, defaultCurrency string) string {
+	return strings.ToUpper(c.DefaultQuery("currency", defaultCurrency))
+}
+
+// GetPortfolioRisk returns the historical and parametric VaR and CVaR of the open positions
+func GetPortfolioRisk(repo repository.PositionRepository, actions repository.CorporateActionRepository, defaultCurrency string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		confidence, err := strconv.ParseFloat(c.DefaultQuery("confidence", "0.95"), 64)
+		if err != nil || confidence <= 0 || confidence >= 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameter. 'confidence' must be a number between 0 and 1."})
+			return
+		}
+		from, err := strconv.Atoi(c.DefaultQuery("from", "12"))
+		if err != nil || from < 2 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameter. 'from' must be an integer greater than 1."})
+			return
+		}
+
+		positions, err := repo.GetAll()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		risk, err := services.FindPortfolioRisk(services.NewSplitAdjuster(actions), positions, confidence, from, reportingCurrency(c, defaultCurrency))
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, risk)
+	}
+}
+
+// GetPortfolioValuation returns the open positions at their quotes and the cash, converted into the reporting currency
+func GetPortfolioValuation(repo repository.PositionRepository, cashRepo repository.CashRepository, defaultCurrency string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		positions, err := repo.GetAll()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		accounts, err := cashRepo.GetAll()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, services.FindPortfolioValuation(positions, accounts, reportingCurrency(c, defaultCurrency)))
+	}
+}
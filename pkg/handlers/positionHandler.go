@@ -1,17 +1,20 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/megajandrox/go-finance-api/pkg/dto"
 	"github.com/megajandrox/go-finance-api/pkg/models"
 	"github.com/megajandrox/go-finance-api/pkg/repository"
+	"github.com/megajandrox/go-finance-api/pkg/services"
 )
 
-func BuyPosition(repo repository.PositionRepository) gin.HandlerFunc {
+func BuyPosition(uow repository.UnitOfWork, actions repository.CorporateActionRepository, ledger *services.CashLedger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var addPosition dto.BuyPosition
 		if err := c.ShouldBindJSON(&addPosition); err != nil {
@@ -27,14 +30,65 @@ func BuyPosition(repo repository.PositionRepository) gin.HandlerFunc {
 
 		// Convertir el entero a uint
 		assetId := uint(idInt)
-		//TODO falta validar que exista la accion y que la cantidad sea positiva
+		//TODO falta validar que exista la accion
 		position, errNew := models.NewPosition(assetId, addPosition.Symbol, addPosition.Price, addPosition.Quantity, addPosition.MarketType)
 		if errNew != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": errNew.Error()})
 			return
 		}
-		if err := repo.Create(position); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err})
+		if addPosition.Fee < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid field. 'fee' cannot be negative."})
+			return
+		}
+		contract, err := optionContract(addPosition.MarketType, addPosition.Option)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if contract != nil {
+			position.SetOption(*contract)
+		}
+		position.FractionalShares = addPosition.Fractional
+		if err := position.ValidateQuantity(position.Quantity); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid field. 'quantity': " + err.Error()})
+			return
+		}
+		position.Currency = services.CurrencyOf(position)
+		if addPosition.Currency != "" {
+			position.Currency = strings.ToUpper(addPosition.Currency)
+		}
+		if position.Currency == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid field. 'currency' is required for the symbols of an unknown exchange."})
+			return
+		}
+		cost := position.Notional(position.EntryPrice, position.Quantity) + addPosition.Fee
+		if err := ledger.CheckFunds(position.Currency, models.BuyDebit, cost); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, models.ErrInsufficientCash) {
+				status = http.StatusBadRequest
+			}
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		if addPosition.StopType != models.NoStop {
+			if err := services.SetPositionStop(services.NewSplitAdjuster(actions), position, addPosition.StopType, addPosition.StopValue); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		// The position is only created if its cost is debited
+		err = uow.Transaction(func(repos repository.Repositories) error {
+			if err := repos.Positions.Create(position); err != nil {
+				return err
+			}
+			return ledger.WithRepository(repos.Cash).RecordBuy(position, addPosition.Fee)
+		})
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, models.ErrInsufficientCash) {
+				status = http.StatusBadRequest
+			}
+			c.JSON(status, gin.H{"error": err.Error()})
 			return
 		}
 
@@ -42,7 +96,7 @@ func BuyPosition(repo repository.PositionRepository) gin.HandlerFunc {
 	}
 }
 
-func SellPosition(repo repository.PositionRepository) gin.HandlerFunc {
+func SellPosition(repo repository.PositionRepository, uow repository.UnitOfWork, ledger *services.CashLedger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var sellPosition dto.SellPosition
 		if err := c.ShouldBindJSON(&sellPosition); err != nil {
@@ -63,19 +117,252 @@ func SellPosition(repo repository.PositionRepository) gin.HandlerFunc {
 			c.JSON(http.StatusNotFound, gin.H{"error": errGetByID.Error()})
 			return
 		}
-		if sellPosition.Quantity > position.Quantity {
+		if position.IsShort() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Short positions are closed by covering them."})
+			return
+		}
+		if err := position.ValidateQuantity(sellPosition.Quantity); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid field. 'quantity': " + err.Error()})
+			return
+		}
+		if sellPosition.Quantity.GreaterThan(position.Quantity) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Incorrect quantity, should be greather than previous one."})
 			return
 		}
-		position.PositionType = models.Sold
+		read := *position
 		position.ExitTime = time.Now()
-		position.Quantity = position.Quantity - sellPosition.Quantity
-		position.Balance = (sellPosition.Price - position.EntryPrice) * float64(sellPosition.Quantity)
-		if err := repo.Update(position); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err})
+		position.Quantity = position.Quantity.Sub(sellPosition.Quantity)
+		// A partial sale keeps the rest of the position open
+		if position.Quantity.IsZero() {
+			position.PositionType = models.Sold
+		}
+		// The balance adds up the P&L of every partial sale
+		position.Balance += position.PnL(sellPosition.Price, sellPosition.Quantity)
+		err = uow.Transaction(func(repos repository.Repositories) error {
+			if err := lockPosition(repos.Positions, read); err != nil {
+				return err
+			}
+			if err := repos.Positions.Update(position); err != nil {
+				return err
+			}
+			return ledger.WithRepository(repos.Cash).RecordSell(position, sellPosition.Price, sellPosition.Quantity, sellPosition.Fee)
+		})
+		if err != nil {
+			status := http.StatusInternalServerError
+			switch {
+			case errors.Is(err, models.ErrInsufficientCash):
+				status = http.StatusBadRequest
+			case errors.Is(err, errPositionChanged):
+				status = http.StatusConflict
+			}
+			c.JSON(status, gin.H{"error": err.Error()})
 			return
 		}
 
 		c.JSON(http.StatusOK, gin.H{"message": "Position created successfully", "position": position})
 	}
 }
+
+func ShortPosition(uow repository.UnitOfWork, ledger *services.CashLedger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var shortPosition dto.ShortPosition
+		if err := c.ShouldBindJSON(&shortPosition); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		idStr := c.Param("id")
+		idInt, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+			return
+		}
+		if shortPosition.Fee < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid field. 'fee' cannot be negative."})
+			return
+		}
+		position, errNew := models.NewShortPosition(uint(idInt), shortPosition.Symbol, shortPosition.Price, shortPosition.Quantity, shortPosition.MarketType, shortPosition.BorrowFeeRate)
+		if errNew != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errNew.Error()})
+			return
+		}
+		contract, err := optionContract(shortPosition.MarketType, shortPosition.Option)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if contract != nil {
+			position.SetOption(*contract)
+		}
+		position.FractionalShares = shortPosition.Fractional
+		if err := position.ValidateQuantity(position.Quantity); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid field. 'quantity': " + err.Error()})
+			return
+		}
+		position.Currency = services.CurrencyOf(position)
+		if shortPosition.Currency != "" {
+			position.Currency = strings.ToUpper(shortPosition.Currency)
+		}
+		if position.Currency == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid field. 'currency' is required for the symbols of an unknown exchange."})
+			return
+		}
+		err = uow.Transaction(func(repos repository.Repositories) error {
+			if err := repos.Positions.Create(position); err != nil {
+				return err
+			}
+			return ledger.WithRepository(repos.Cash).RecordShort(position, shortPosition.Fee)
+		})
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, models.ErrInsufficientCash) {
+				status = http.StatusBadRequest
+			}
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Short position created successfully", "position": position})
+	}
+}
+
+func CoverPosition(repo repository.PositionRepository, uow repository.UnitOfWork, ledger *services.CashLedger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var coverPosition dto.CoverPosition
+		if err := c.ShouldBindJSON(&coverPosition); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		idStr := c.Param("idPosition")
+		idInt, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+			return
+		}
+		if coverPosition.Price <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid field. 'price' must be positive."})
+			return
+		}
+		if coverPosition.Fee < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid field. 'fee' cannot be negative."})
+			return
+		}
+
+		position, errGetByID := repo.GetByID(uint(idInt))
+		if errGetByID != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": errGetByID.Error()})
+			return
+		}
+		read := *position
+		borrowFees, err := position.Cover(coverPosition.Price, coverPosition.Quantity, time.Now())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		cost := position.Notional(coverPosition.Price, coverPosition.Quantity) + coverPosition.Fee + borrowFees
+		if err := ledger.CheckFunds(services.CurrencyOf(position), models.BuyDebit, cost); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, models.ErrInsufficientCash) {
+				status = http.StatusBadRequest
+			}
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		err = uow.Transaction(func(repos repository.Repositories) error {
+			if err := lockPosition(repos.Positions, read); err != nil {
+				return err
+			}
+			if err := repos.Positions.Update(position); err != nil {
+				return err
+			}
+			return ledger.WithRepository(repos.Cash).RecordCover(position, coverPosition.Price, coverPosition.Quantity, coverPosition.Fee, borrowFees)
+		})
+		if err != nil {
+			status := http.StatusInternalServerError
+			switch {
+			case errors.Is(err, models.ErrInsufficientCash):
+				status = http.StatusBadRequest
+			case errors.Is(err, errPositionChanged):
+				status = http.StatusConflict
+			}
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Position covered successfully", "position": position, "borrow_fees": borrowFees})
+	}
+}
+
+// errPositionChanged means the position was written by another trade or a corporate action after it was read
+var errPositionChanged = errors.New("the position changed while it was traded, try again")
+
+// lockPosition locks the position until the transaction ends and checks it is still the one that was read
+func lockPosition(repo repository.PositionRepository, read models.Position) error {
+	locked, err := repo.GetByIDForUpdate(read.ID)
+	if err != nil {
+		return err
+	}
+	if locked.PositionType != read.PositionType || !locked.Quantity.Equal(read.Quantity) || !locked.BorrowAccruedAt.Equal(read.BorrowAccruedAt) {
+		return errPositionChanged
+	}
+	return nil
+}
+
+func GetPosition(repo repository.PositionRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("idPosition")
+		idInt, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+			return
+		}
+
+		position, errGetByID := repo.GetByID(uint(idInt))
+		if errGetByID != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": errGetByID.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"position": position})
+	}
+}
+
+func SetPositionStop(repo repository.PositionRepository, actions repository.CorporateActionRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var positionStop dto.PositionStop
+		if err := c.ShouldBindJSON(&positionStop); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		idStr := c.Param("idPosition")
+		idInt, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+			return
+		}
+
+		position, errGetByID := repo.GetByID(uint(idInt))
+		if errGetByID != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": errGetByID.Error()})
+			return
+		}
+		if position.PositionType != models.Bought || !position.Quantity.IsPositive() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Stops can only be set on open positions."})
+			return
+		}
+		if err := services.SetPositionStop(services.NewSplitAdjuster(actions), position, positionStop.StopType, positionStop.Value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		updated, err := repo.UpdateStop(position)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !updated {
+			c.JSON(http.StatusConflict, gin.H{"error": "The position changed while its stop was set, try again."})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Position stop updated successfully", "position": position})
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/megajandrox/go-finance-api/pkg/dto"
+	"github.com/megajandrox/go-finance-api/pkg/models"
+	"github.com/megajandrox/go-finance-api/pkg/repository"
+	"github.com/megajandrox/go-finance-api/pkg/services"
+)
+
+// RuleResponse represents the JSON structure for the rule evaluation response
+type RuleResponse struct {
+	Symbol     string   `json:"symbol"`
+	Expression string   `json:"expression"`
+	Matched    bool     `json:"matched"`
+	Value      *float64 `json:"value,omitempty"`
+	TimeStamp  string   `json:"timestamp"`
+}
+
+// EvaluateRule evaluates an expression on the latest bar of the symbol
+func EvaluateRule(actions repository.CorporateActionRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var rule dto.EvaluateRule
+		if err := c.ShouldBindJSON(&rule); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if rule.Symbol == "" || rule.Expression == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Fields 'symbol' and 'expression' are required."})
+			return
+		}
+		if rule.From < 2 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid field. 'from' must be greater than 1."})
+			return
+		}
+		if !IsValidInterval(Interval(rule.Interval)) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid field. " + invalidIntervalMessage})
+			return
+		}
+
+		expression, err := models.ParseExpression(rule.Expression)
+		if err != nil {
+			var parseErr *models.ParseError
+			if errors.As(err, &parseErr) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": parseErr.Error(), "position": parseErr.Position})
+				return
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		marketDataList, err := services.FindMarketDataBySymbol(services.NewSplitAdjuster(actions), rule.Symbol, rule.From, rule.Interval)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		value, err := expression.Latest(marketDataList)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		response := RuleResponse{
+			Symbol:     rule.Symbol,
+			Expression: expression.String(),
+			Matched:    !math.IsNaN(value) && value != 0,
+			TimeStamp:  services.ConvertTimestamp(rule.Symbol, marketDataList[len(marketDataList)-1].TimeStamp),
+		}
+		if !math.IsNaN(value) {
+			response.Value = &value
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
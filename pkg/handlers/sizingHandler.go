@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/megajandrox/go-finance-api/pkg/dto"
+	"github.com/megajandrox/go-finance-api/pkg/models"
+	"github.com/megajandrox/go-finance-api/pkg/repository"
+	"github.com/megajandrox/go-finance-api/pkg/services"
+)
+
+const sizingATRPeriod = 14
+
+// CalculatePositionSize returns the quantity to buy for the risk of the account
+func CalculatePositionSize(repo repository.PositionRepository, actions repository.CorporateActionRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request dto.PositionSizeRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if request.Symbol == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Field 'symbol' is required."})
+			return
+		}
+		if request.StopPrice == 0 && request.ATRMultiple <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Either 'stop_price' or 'atr_multiple' is required."})
+			return
+		}
+
+		instrument := &models.Position{Symbol: request.Symbol, MarketType: request.MarketType, FractionalShares: request.Fractional}
+		contract, err := optionContract(request.MarketType, request.Option)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if contract != nil {
+			instrument.SetOption(*contract)
+		}
+
+		entryPrice := request.EntryPrice
+		if entryPrice == 0 {
+			quote, err := services.FindQuote(request.Symbol)
+			if err != nil {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			entryPrice = quote.RegularMarketPrice
+		}
+		stopPrice := request.StopPrice
+		if stopPrice == 0 {
+			atr, err := services.FindATRBySymbol(services.NewSplitAdjuster(actions), request.Symbol, sizingATRPeriod)
+			if err != nil {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+				return
+			}
+			if stopPrice, err = models.ATRStopPrice(entryPrice, atr, request.ATRMultiple); err != nil {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		size, err := models.CalculatePositionSize(request.Equity, request.RiskPercent, entryPrice, stopPrice, instrument)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if request.MaxConcentration > 0 {
+			positions, err := repo.GetAll()
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			if err := size.CapByConcentration(request.Equity, request.MaxConcentration, models.OpenExposure(positions, request.Symbol)); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{"symbol": request.Symbol, "size": size})
+	}
+}
@@ -1,18 +1,36 @@
 package handlers
 
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/megajandrox/go-finance-api/pkg/calendar"
+	"github.com/megajandrox/go-finance-api/pkg/models"
+)
+
 type Interval string
 
 const (
 	// Define intervals
-	SixtyMins Interval = "60m"
-	OneHour   Interval = "1h"
-	OneDay    Interval = "1d"
+	OneMin      Interval = "1m"
+	FiveMins    Interval = "5m"
+	FifteenMins Interval = "15m"
+	ThirtyMins  Interval = "30m"
+	SixtyMins   Interval = "60m"
+	OneHour     Interval = "1h"
+	OneDay      Interval = "1d"
+	OneWeek     Interval = "1wk"
+	OneMonth    Interval = "1mo"
 )
 
 var ValidIntervals = []Interval{
-	SixtyMins, OneHour, OneDay,
+	OneMin, FiveMins, FifteenMins, ThirtyMins, SixtyMins, OneHour, OneDay, OneWeek, OneMonth,
 }
 
+// invalidIntervalMessage is the error returned for an 'interval' out of ValidIntervals
+const invalidIntervalMessage = "'interval' must be one of 1m, 5m, 15m, 30m, 60m, 1h, 1d, 1wk or 1mo."
+
 // IsValidInterval checks if the interval is valid.
 func IsValidInterval(interval Interval) bool {
 	for _, v := range ValidIntervals {
@@ -22,3 +40,39 @@ func IsValidInterval(interval Interval) bool {
 	}
 	return false
 }
+
+// ValidateRange checks that the window is ordered, does not start in the future and is within the lookback of the interval
+func ValidateRange(interval Interval, start time.Time, end time.Time) error {
+	now := time.Now()
+	if !start.Before(end) {
+		return errors.New("'start' must be before 'end'")
+	}
+	if start.After(now) {
+		return errors.New("'start' cannot be in the future")
+	}
+	if lookback := models.MaxLookback(string(interval)); lookback > 0 && start.Before(now.Add(-lookback)) {
+		return fmt.Errorf("the %s interval only reaches back %d days, 'start' must be after %s",
+			interval, int(lookback.Hours()/24), now.Add(-lookback).Format("2006-01-02"))
+	}
+	return nil
+}
+
+// parseDateTime accepts an RFC 3339 timestamp or an ISO date, the day of the exchange
+func parseDateTime(value string, exchange *calendar.Exchange) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", value, exchange.Location())
+}
+
+// parseEndDateTime is parseDateTime but an ISO date includes the whole day
+func parseEndDateTime(value string, exchange *calendar.Exchange) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, exchange.Location())
+	if err != nil {
+		return t, err
+	}
+	return t.AddDate(0, 0, 1).Add(-time.Second), nil
+}
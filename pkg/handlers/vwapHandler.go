@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/megajandrox/go-finance-api/pkg/calendar"
+	"github.com/megajandrox/go-finance-api/pkg/models"
+	"github.com/megajandrox/go-finance-api/pkg/repository"
+	"github.com/megajandrox/go-finance-api/pkg/services"
+)
+
+// VWAPResponse represents the JSON structure for the VWAP response
+type VWAPResponse struct {
+	Symbol       string             `json:"symbol"`
+	Exchange     string             `json:"exchange"`
+	VWAPResult   string             `json:"vwap_result"`
+	VWAPAnalysis string             `json:"vwap_analysis"`
+	Anchor       string             `json:"anchor,omitempty"`
+	Session      []models.VWAPPoint `json:"session"`
+	Anchored     []models.VWAPPoint `json:"anchored,omitempty"`
+}
+
+// GetVWAP returns the session VWAP with its bands, anchored at 'anchor' or at the entry of 'position_id'
+func GetVWAP(repo repository.PositionRepository, actions repository.CorporateActionRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		symbol := c.Param("symbol")
+		exchange := calendar.ForSymbol(symbol)
+		from, err := strconv.Atoi(c.Query("from"))
+		if err != nil || from < 2 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameter. 'from' must be an integer greater than 1."})
+			return
+		}
+		intervalParam := c.Query("interval")
+		if !IsValidInterval(Interval(intervalParam)) || !models.IsIntradayInterval(intervalParam) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameter. VWAP requires an intraday 'interval'."})
+			return
+		}
+
+		var anchor time.Time
+		if anchorParam := c.Query("anchor"); anchorParam != "" {
+			anchor, err = parseDateTime(anchorParam, exchange)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameter. 'anchor' must be a date (2006-01-02) or an RFC 3339 timestamp."})
+				return
+			}
+		} else if positionParam := c.Query("position_id"); positionParam != "" {
+			positionID, err := strconv.Atoi(positionParam)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameter. 'position_id' must be an integer."})
+				return
+			}
+			position, err := repo.GetByID(uint(positionID))
+			if err != nil {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			anchor = position.EntryTime
+		}
+
+		vwap, err := services.FindVWAPBySymbol(services.NewSplitAdjuster(actions), symbol, from, intervalParam, anchor)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		response := VWAPResponse{
+			Symbol:       symbol,
+			Exchange:     exchange.Code,
+			VWAPResult:   vwap.TrendType.String(),
+			VWAPAnalysis: vwap.Result,
+			Session:      vwap.Session,
+			Anchored:     vwap.Anchored,
+		}
+		if !anchor.IsZero() {
+			response.Anchor = exchange.FormatTimestamp(anchor.Unix())
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -52,7 +52,8 @@ func (adr *ADX) calculateDM(current, previous BasicMarketData) (float64, float64
 
 // CalculateADX calculates the ADX for a given period
 func (adr *ADX) calculateADX(marketDataList []BasicMarketData, period int) ([]float64, error) {
-	if len(marketDataList) < period+1 {
+	// The DX is averaged over a second period before the first ADX, and the latest needs one more bar
+	if len(marketDataList) < 2*period+2 {
 		return nil, fmt.Errorf("not enough data to calculate ADX for the given period")
 	}
 
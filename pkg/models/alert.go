@@ -0,0 +1,96 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AlertType int
+
+// Define constants representing the enumerator values
+const (
+	PriceCrossesAbove AlertType = iota
+	PriceCrossesBelow
+	RSIEntersOversold
+	RSIEntersOverbought
+	MACDBullishCrossover
+	MACDBearishCrossover
+	ExpressionMatches
+)
+
+func (t AlertType) String() string {
+	switch t {
+	case PriceCrossesAbove:
+		return "Price Crosses Above"
+	case PriceCrossesBelow:
+		return "Price Crosses Below"
+	case RSIEntersOversold:
+		return "RSI Enters Oversold"
+	case RSIEntersOverbought:
+		return "RSI Enters Overbought"
+	case MACDBullishCrossover:
+		return "MACD Bullish Crossover"
+	case MACDBearishCrossover:
+		return "MACD Bearish Crossover"
+	case ExpressionMatches:
+		return "Expression Matches"
+	default:
+		return "Unknown AlertType"
+	}
+}
+
+/*
+ * An alert is triggered when its condition goes from false to true between two evaluations
+ */
+type Alert struct {
+	gorm.Model
+	Symbol          string    `json:"symbol"`            // Financial asset symbol
+	AlertType       AlertType `json:"alert_type"`        // Condition to watch
+	Level           float64   `json:"level"`             // Price level for the price alerts
+	Expression      string    `json:"expression"`        // Rule for the expression alerts
+	Interval        string    `json:"interval"`          // Interval of the bars used by indicator alerts
+	From            int       `json:"from"`              // Months of history used by indicator alerts
+	Active          bool      `json:"active"`            // Only active alerts are evaluated
+	LastState       bool      `json:"last_state"`        // Condition result on the last evaluation
+	LastEvaluatedAt time.Time `json:"last_evaluated_at"` // Zero until the first evaluation
+}
+
+func NewAlert(symb string, alertType AlertType, level float64, expression string, interval string, from int) (*Alert, error) {
+	if symb == "" {
+		return nil, errors.New("symbol cannot be empty")
+	}
+	if alertType < PriceCrossesAbove || alertType > ExpressionMatches {
+		return nil, errors.New("unknown alert type")
+	}
+	if (alertType == PriceCrossesAbove || alertType == PriceCrossesBelow) && level <= 0 {
+		return nil, errors.New("level must be positive for price alerts")
+	}
+	if alertType == ExpressionMatches {
+		if _, err := ParseExpression(expression); err != nil {
+			return nil, err
+		}
+	}
+	if interval == "" {
+		interval = "1d"
+	}
+	if from < 2 {
+		from = 12
+	}
+	return &Alert{Symbol: symb, AlertType: alertType, Level: level, Expression: expression, Interval: interval, From: from, Active: true}, nil
+}
+
+/*
+ * Record of a triggered alert
+ */
+type AlertEvent struct {
+	gorm.Model
+	AlertID     uint      `json:"alert_id"`     // Foreign key to Alert
+	Symbol      string    `json:"symbol"`       // Financial asset symbol
+	AlertType   AlertType `json:"alert_type"`   // Condition that was met
+	Value       float64   `json:"value"`        // Price or indicator value when triggered
+	Message     string    `json:"message"`      // Description of the event
+	TriggeredAt time.Time `json:"triggered_at"` // Evaluation time
+	Delivered   bool      `json:"delivered"`    // Every sink accepted the event
+}
@@ -0,0 +1,241 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/megajandrox/go-finance-api/pkg/calendar"
+)
+
+/*
+ * Statistical risk view of a symbol against a benchmark
+ */
+type RiskAnalytics struct {
+	Symbol                 string   `json:"symbol"`
+	Benchmark              string   `json:"benchmark,omitempty"`
+	Observations           int      `json:"observations"`                   // Number of returns used for the volatility
+	CloseToCloseVolatility float64  `json:"close_to_close_volatility"`      // Annualized standard deviation of the log returns
+	ParkinsonVolatility    float64  `json:"parkinson_volatility"`           // Annualized volatility estimated from High and Low
+	Beta                   *float64 `json:"beta,omitempty"`                 // Sensitivity of the returns to the benchmark returns
+	Correlation            *float64 `json:"correlation,omitempty"`          // Pearson correlation with the benchmark returns
+	BenchmarkObservations  int      `json:"benchmark_observations"`         // Number of returns aligned with the benchmark
+	BenchmarkVolatility    float64  `json:"benchmark_volatility,omitempty"` // Annualized volatility of the benchmark
+}
+
+// PeriodsPerYear returns the number of bars of the interval in a trading year of the exchange
+func PeriodsPerYear(interval string, exchange *calendar.Exchange) float64 {
+	tradingDays := exchange.TradingDaysPerYear()
+	switch interval {
+	case "1d":
+		return tradingDays
+	case "1wk":
+		return 52
+	case "1mo":
+		return 12
+	default:
+		// Intraday bars, as many as fit in the sessions
+		duration, err := time.ParseDuration(interval)
+		if err != nil || duration <= 0 {
+			return tradingDays
+		}
+		return tradingDays * exchange.SessionHours() * float64(time.Hour) / float64(duration)
+	}
+}
+
+// LogReturns returns the log returns of the closes
+func LogReturns(marketDataList []BasicMarketData) []float64 {
+	var returns []float64
+	for i := 1; i < len(marketDataList); i++ {
+		if marketDataList[i-1].Close > 0 && marketDataList[i].Close > 0 {
+			returns = append(returns, math.Log(marketDataList[i].Close/marketDataList[i-1].Close))
+		}
+	}
+	return returns
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// covariance returns the sample covariance of two series of the same length
+func covariance(a, b []float64) float64 {
+	meanA, meanB := mean(a), mean(b)
+	sum := 0.0
+	for i := range a {
+		sum += (a[i] - meanA) * (b[i] - meanB)
+	}
+	return sum / float64(len(a)-1)
+}
+
+// StandardDeviation returns the sample standard deviation
+func StandardDeviation(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	return math.Sqrt(covariance(values, values))
+}
+
+// CloseToCloseVolatility returns the annualized volatility of the log returns
+func CloseToCloseVolatility(marketDataList []BasicMarketData, periodsPerYear float64) (float64, error) {
+	returns := LogReturns(marketDataList)
+	if len(returns) < 2 {
+		return 0, errors.New("not enough data to calculate the volatility")
+	}
+	return StandardDeviation(returns) * math.Sqrt(periodsPerYear), nil
+}
+
+// ParkinsonVolatility returns the annualized volatility estimated from the High/Low range
+func ParkinsonVolatility(marketDataList []BasicMarketData, periodsPerYear float64) (float64, error) {
+	sum := 0.0
+	count := 0
+	for _, d := range marketDataList {
+		if d.High > 0 && d.Low > 0 {
+			logRange := math.Log(d.High / d.Low)
+			sum += logRange * logRange
+			count++
+		}
+	}
+	if count < 2 {
+		return 0, errors.New("not enough data to calculate the Parkinson volatility")
+	}
+	return math.Sqrt(sum/(4*math.Ln2*float64(count))) * math.Sqrt(periodsPerYear), nil
+}
+
+// barKey identifies the bars of different symbols that belong to the same period. Daily bars are keyed
+// by the trading day of their exchange, so sessions line up across time zones and not on UTC midnights.
+func barKey(exchange *calendar.Exchange, timestamp int64, daily bool) int64 {
+	if daily {
+		year, month, day := exchange.TradingDay(timestamp).Date()
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix()
+	}
+	return timestamp
+}
+
+// AlignedReturns returns the log returns of both series on the periods they have in common
+func AlignedReturns(symbolA string, a []BasicMarketData, symbolB string, b []BasicMarketData, daily bool) ([]float64, []float64) {
+	exchangeA, exchangeB := calendar.ForSymbol(symbolA), calendar.ForSymbol(symbolB)
+	closesB := map[int64]float64{}
+	for _, d := range b {
+		closesB[barKey(exchangeB, d.TimeStamp, daily)] = d.Close
+	}
+	var keys []int64
+	closesA := map[int64]float64{}
+	for _, d := range a {
+		key := barKey(exchangeA, d.TimeStamp, daily)
+		if _, ok := closesB[key]; ok {
+			if _, seen := closesA[key]; !seen {
+				keys = append(keys, key)
+			}
+			closesA[key] = d.Close
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	var returnsA, returnsB []float64
+	for i := 1; i < len(keys); i++ {
+		prevA, currA := closesA[keys[i-1]], closesA[keys[i]]
+		prevB, currB := closesB[keys[i-1]], closesB[keys[i]]
+		if prevA > 0 && currA > 0 && prevB > 0 && currB > 0 {
+			returnsA = append(returnsA, math.Log(currA/prevA))
+			returnsB = append(returnsB, math.Log(currB/prevB))
+		}
+	}
+	return returnsA, returnsB
+}
+
+// Correlation returns the Pearson correlation of two series of the same length
+func Correlation(a, b []float64) (float64, error) {
+	if len(a) != len(b) || len(a) < 3 {
+		return 0, errors.New("not enough aligned data to calculate the correlation")
+	}
+	deviationA, deviationB := StandardDeviation(a), StandardDeviation(b)
+	if deviationA == 0 || deviationB == 0 {
+		return 0, errors.New("the correlation is not defined for a constant series")
+	}
+	return covariance(a, b) / (deviationA * deviationB), nil
+}
+
+// Beta returns the covariance of the returns with the benchmark over the variance of the benchmark
+func Beta(returns, benchmarkReturns []float64) (float64, error) {
+	if len(returns) != len(benchmarkReturns) || len(returns) < 3 {
+		return 0, errors.New("not enough aligned data to calculate the beta")
+	}
+	variance := covariance(benchmarkReturns, benchmarkReturns)
+	if variance == 0 {
+		return 0, errors.New("the beta is not defined for a constant benchmark")
+	}
+	return covariance(returns, benchmarkReturns) / variance, nil
+}
+
+// CalculateRiskAnalytics computes the volatility of the symbol and, with a benchmark, its beta and correlation
+func CalculateRiskAnalytics(symbol string, marketDataList []BasicMarketData, benchmark string, benchmarkData []BasicMarketData, interval string) (*RiskAnalytics, error) {
+	periodsPerYear := PeriodsPerYear(interval, calendar.ForSymbol(symbol))
+	analytics := &RiskAnalytics{Symbol: symbol, Benchmark: benchmark, Observations: len(LogReturns(marketDataList))}
+	var err error
+	if analytics.CloseToCloseVolatility, err = CloseToCloseVolatility(marketDataList, periodsPerYear); err != nil {
+		return nil, err
+	}
+	if analytics.ParkinsonVolatility, err = ParkinsonVolatility(marketDataList, periodsPerYear); err != nil {
+		return nil, err
+	}
+	if benchmark == "" {
+		return analytics, nil
+	}
+
+	returns, benchmarkReturns := AlignedReturns(symbol, marketDataList, benchmark, benchmarkData, IsDailyInterval(interval))
+	analytics.BenchmarkObservations = len(returns)
+	beta, err := Beta(returns, benchmarkReturns)
+	if err != nil {
+		return nil, err
+	}
+	correlation, err := Correlation(returns, benchmarkReturns)
+	if err != nil {
+		return nil, err
+	}
+	analytics.Beta, analytics.Correlation = &beta, &correlation
+	analytics.BenchmarkVolatility = StandardDeviation(benchmarkReturns) * math.Sqrt(PeriodsPerYear(interval, calendar.ForSymbol(benchmark)))
+	return analytics, nil
+}
+
+/*
+ * Pairwise correlation of the returns of several symbols
+ */
+type CorrelationMatrix struct {
+	Symbols []string     `json:"symbols"`
+	Matrix  [][]*float64 `json:"matrix"` // Null when the pair does not have enough aligned data
+}
+
+// CalculateCorrelationMatrix correlates the returns of every pair of symbols on their common periods
+func CalculateCorrelationMatrix(marketData map[string][]BasicMarketData, daily bool) CorrelationMatrix {
+	symbols := make([]string, 0, len(marketData))
+	for symbol := range marketData {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	matrix := make([][]*float64, len(symbols))
+	for i := range symbols {
+		matrix[i] = make([]*float64, len(symbols))
+	}
+	for i := range symbols {
+		for j := i; j < len(symbols); j++ {
+			a, b := AlignedReturns(symbols[i], marketData[symbols[i]], symbols[j], marketData[symbols[j]], daily)
+			correlation, err := Correlation(a, b)
+			if err != nil {
+				continue
+			}
+			matrix[i][j] = &correlation
+			matrix[j][i] = &correlation
+		}
+	}
+	return CorrelationMatrix{Symbols: symbols, Matrix: matrix}
+}
+
+// IsDailyInterval reports whether the bars of the interval are aligned by date instead of timestamp
+func IsDailyInterval(interval string) bool {
+	return interval == "1d" || interval == "1wk" || interval == "1mo"
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+// stampedBars builds one daily bar per close, the first one stamped at start
+func stampedBars(start int64, closes ...float64) []BasicMarketData {
+	bars := make([]BasicMarketData, len(closes))
+	for i, c := range closes {
+		bars[i] = BasicMarketData{Open: c, High: c, Low: c, Close: c, TimeStamp: start + int64(i)*86400}
+	}
+	return bars
+}
+
+func TestAlignedReturns(t *testing.T) {
+	// Sessions from January 2nd 2024: AAPL stamped at 14:30 UTC, GGAL.BA after the close at 23:00 in Buenos
+	// Aires, which is 02:00 UTC of the next day
+	nyse := stampedBars(1704205800, 100, 110, 121, 108.9)
+	byma := stampedBars(1704247200, 50, 45, 54, 59.4)
+	tests := []struct {
+		name         string
+		symbolA      string
+		a            []BasicMarketData
+		symbolB      string
+		b            []BasicMarketData
+		daily        bool
+		wantA, wantB []float64
+	}{
+		{"same session across time zones", "AAPL", nyse, "GGAL.BA", byma, true,
+			[]float64{math.Log(1.1), math.Log(1.1), math.Log(0.9)}, []float64{math.Log(0.9), math.Log(1.2), math.Log(1.1)}},
+		{"missing session", "AAPL", nyse, "GGAL.BA", append(byma[:1:1], byma[2:]...), true,
+			[]float64{math.Log(1.21), math.Log(0.9)}, []float64{math.Log(54.0 / 50), math.Log(1.1)}},
+		// Intraday bars only line up on the same timestamp
+		{"intraday", "AAPL", nyse, "GGAL.BA", byma, false, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotA, gotB := AlignedReturns(tt.symbolA, tt.a, tt.symbolB, tt.b, tt.daily)
+			if len(gotA) != len(tt.wantA) || len(gotB) != len(tt.wantB) {
+				t.Fatalf("returns = %v %v, want %v %v", gotA, gotB, tt.wantA, tt.wantB)
+			}
+			for i := range tt.wantA {
+				if math.Abs(gotA[i]-tt.wantA[i]) > 1e-12 || math.Abs(gotB[i]-tt.wantB[i]) > 1e-12 {
+					t.Errorf("return %d = %v %v, want %v %v", i, gotA[i], gotB[i], tt.wantA[i], tt.wantB[i])
+				}
+			}
+		})
+	}
+}
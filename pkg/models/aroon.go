@@ -0,0 +1,89 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Aroon struct {
+	symbol     string
+	AroonUp    []float64
+	AroonDown  []float64
+	Oscillator []float64
+	TrendType  TrendType
+	Result     string
+}
+
+func NewAroon(symbol string) (*Aroon, error) {
+	if symbol == "" {
+		return nil, errors.New("symbol cannot be empty")
+	}
+	return &Aroon{symbol: symbol}, nil
+}
+
+func (i *Aroon) SetIndex(indexes *Indexes) *Indexes {
+	indexes.Aroon = *i
+	return indexes
+}
+
+// calculateAroon calculates Aroon Up and Down from the bars since the highest high and the lowest low
+func (a *Aroon) calculateAroon(marketDataList []BasicMarketData, period int) ([]float64, []float64, error) {
+	_, highs, lows, _ := ExtractMarketData(marketDataList)
+	if len(highs) < period+1 {
+		return nil, nil, fmt.Errorf("not enough data to calculate Aroon for the given period")
+	}
+
+	up := make([]float64, len(highs)-period)
+	down := make([]float64, len(highs)-period)
+	for i := period; i < len(highs); i++ {
+		highest, lowest := i-period, i-period
+		for j := i - period; j <= i; j++ {
+			if highs[j] >= highs[highest] {
+				highest = j
+			}
+			if lows[j] <= lows[lowest] {
+				lowest = j
+			}
+		}
+		up[i-period] = 100 * float64(period-(i-highest)) / float64(period)
+		down[i-period] = 100 * float64(period-(i-lowest)) / float64(period)
+	}
+	return up, down, nil
+}
+
+// Analyze analyzes Aroon Up, Down and the oscillator
+func (a *Aroon) Analyze(marketDataList []BasicMarketData) error {
+	up, down, err := a.calculateAroon(marketDataList, 25)
+	if err != nil {
+		a.TrendType = None
+		a.Result = fmt.Sprintf("It is not possible to calculate Aroon due to: %s", err)
+		return fmt.Errorf("It is not possible to calculate Aroon due to: %s", err)
+	}
+	a.AroonUp = up
+	a.AroonDown = down
+	a.Oscillator = make([]float64, len(up))
+	for i := range up {
+		a.Oscillator[i] = up[i] - down[i]
+	}
+
+	latestUp, latestDown := up[len(up)-1], down[len(down)-1]
+	latestOscillator := a.Oscillator[len(a.Oscillator)-1]
+	switch {
+	case latestUp >= 70 && latestDown <= 30:
+		a.TrendType = Uptrend
+		a.Result = fmt.Sprintf("Aroon Up %.2f / Down %.2f, indicating a strong uptrend.", latestUp, latestDown)
+	case latestDown >= 70 && latestUp <= 30:
+		a.TrendType = Downtrend
+		a.Result = fmt.Sprintf("Aroon Up %.2f / Down %.2f, indicating a strong downtrend.", latestUp, latestDown)
+	case latestOscillator > 0:
+		a.TrendType = Potential_Uptrend
+		a.Result = fmt.Sprintf("Aroon oscillator is %.2f, indicating a potential uptrend.", latestOscillator)
+	case latestOscillator < 0:
+		a.TrendType = Potential_Downtrend
+		a.Result = fmt.Sprintf("Aroon oscillator is %.2f, indicating a potential downtrend.", latestOscillator)
+	default:
+		a.TrendType = Neutral
+		a.Result = "Aroon oscillator is 0, there is no clear trend."
+	}
+	return nil
+}
@@ -34,7 +34,7 @@ func (atr *ATR) calculateTR(high, low, prevClose float64) float64 {
 
 // CalculateATR calculates the Average True Range (ATR) for a given period
 func (atr *ATR) calculateATR(marketDataList []BasicMarketData, period int) ([]float64, error) {
-	if len(marketDataList) < period {
+	if len(marketDataList) <= period {
 		return nil, fmt.Errorf("not enough data to calculate ATR for the given period")
 	}
 
@@ -47,7 +47,7 @@ func (atr *ATR) calculateATR(marketDataList []BasicMarketData, period int) ([]fl
 	}
 
 	// Initialize ATR array
-	atrArray := make([]float64, len(marketDataList)-(period-1))
+	atrArray := make([]float64, len(marketDataList)-period)
 
 	// Calculate the initial ATR using the average of the first 'period' TR values
 	sumTR := 0.0
@@ -64,6 +64,15 @@ func (atr *ATR) calculateATR(marketDataList []BasicMarketData, period int) ([]fl
 	return atrArray, nil
 }
 
+// CalculateLatestATR returns the last ATR value for a given period
+func (atr *ATR) CalculateLatestATR(marketDataList []BasicMarketData, period int) (float64, error) {
+	atrArray, err := atr.calculateATR(marketDataList, period)
+	if err != nil {
+		return 0, err
+	}
+	return atrArray[len(atrArray)-1], nil
+}
+
 // analyze ATR analyzes
 func (atr *ATR) Analyze(marketDataList []BasicMarketData) error {
 	var trendType TrendType
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+// barsWithRanges builds bars closing at 10 whose high minus low is each range, so the true range is the range
+func barsWithRanges(ranges ...float64) []BasicMarketData {
+	bars := make([]BasicMarketData, len(ranges))
+	for i, r := range ranges {
+		bars[i] = BasicMarketData{Open: 10, High: 10 + r/2, Low: 10 - r/2, Close: 10, TimeStamp: int64(1704067200 + i*86400)}
+	}
+	return bars
+}
+
+func TestCalculateLatestATR(t *testing.T) {
+	tests := []struct {
+		name    string
+		bars    []BasicMarketData
+		period  int
+		want    float64
+		wantErr bool
+	}{
+		{"exactly the period", barsWithRanges(2, 2, 4, 6), 4, 0, true},
+		// The first true range needs the previous close, the seed averages 2, 4 and 6
+		{"only the seed average", barsWithRanges(2, 2, 4, 6), 3, 4, false},
+		// Wilder smoothing: (4*2 + 8) / 3
+		{"one smoothed value", barsWithRanges(2, 2, 4, 6, 8), 3, 16.0 / 3, false},
+		{"constant range", barsWithRanges(1, 1, 1, 1, 1, 1, 1, 1), 5, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&ATR{}).CalculateLatestATR(tt.bars, tt.period)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("ATR = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"errors"
+	"math"
+)
+
+/*
+ * Black-Scholes value and sensitivities of an option, or of a position when scaled
+ */
+type OptionGreeks struct {
+	Value float64 `json:"value"` // Theoretical price
+	Delta float64 `json:"delta"` // Change of the value per unit of the underlying price
+	Gamma float64 `json:"gamma"` // Change of the delta per unit of the underlying price
+	Theta float64 `json:"theta"` // Change of the value per calendar day
+	Vega  float64 `json:"vega"`  // Change of the value per volatility point
+	Rho   float64 `json:"rho"`   // Change of the value per rate point
+}
+
+// Scale multiplies the value and the greeks by factor, e.g. the signed contracts times the multiplier
+func (g OptionGreeks) Scale(factor float64) OptionGreeks {
+	return OptionGreeks{
+		Value: g.Value * factor,
+		Delta: g.Delta * factor,
+		Gamma: g.Gamma * factor,
+		Theta: g.Theta * factor,
+		Vega:  g.Vega * factor,
+		Rho:   g.Rho * factor,
+	}
+}
+
+// normalCDF returns the standard normal cumulative distribution
+func normalCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+// intrinsicValue returns what exercising the option at the spot is worth
+func intrinsicValue(optionType OptionType, spot float64, strike float64) float64 {
+	if optionType == Put {
+		return math.Max(strike-spot, 0)
+	}
+	return math.Max(spot-strike, 0)
+}
+
+// BlackScholes prices a European option without dividends. The rate and the volatility are annual
+// decimals and years is the time to expiry.
+func BlackScholes(optionType OptionType, spot float64, strike float64, years float64, rate float64, volatility float64) (*OptionGreeks, error) {
+	if spot <= 0 || strike <= 0 {
+		return nil, errors.New("spot and strike must be positive")
+	}
+	if years <= 0 {
+		// Expired, worth its intrinsic value
+		greeks := &OptionGreeks{Value: intrinsicValue(optionType, spot, strike)}
+		if greeks.Value > 0 {
+			greeks.Delta = 1
+			if optionType == Put {
+				greeks.Delta = -1
+			}
+		}
+		return greeks, nil
+	}
+	if volatility <= 0 {
+		return nil, errors.New("volatility must be positive")
+	}
+	sqrtYears := math.Sqrt(years)
+	d1 := (math.Log(spot/strike) + (rate+volatility*volatility/2)*years) / (volatility * sqrtYears)
+	d2 := d1 - volatility*sqrtYears
+	discount := math.Exp(-rate * years)
+	density := normalDensity(d1)
+
+	greeks := &OptionGreeks{
+		Gamma: density / (spot * volatility * sqrtYears),
+		Vega:  spot * density * sqrtYears / 100,
+	}
+	decay := -spot * density * volatility / (2 * sqrtYears)
+	if optionType == Put {
+		greeks.Value = strike*discount*normalCDF(-d2) - spot*normalCDF(-d1)
+		greeks.Delta = normalCDF(d1) - 1
+		greeks.Theta = (decay + rate*strike*discount*normalCDF(-d2)) / 365
+		greeks.Rho = -strike * years * discount * normalCDF(-d2) / 100
+	} else {
+		greeks.Value = spot*normalCDF(d1) - strike*discount*normalCDF(d2)
+		greeks.Delta = normalCDF(d1)
+		greeks.Theta = (decay - rate*strike*discount*normalCDF(d2)) / 365
+		greeks.Rho = strike * years * discount * normalCDF(d2) / 100
+	}
+	return greeks, nil
+}
+
+// ImpliedVolatility returns the volatility at which Black-Scholes gives the price, found by bisection
+func ImpliedVolatility(optionType OptionType, price float64, spot float64, strike float64, years float64, rate float64) (float64, error) {
+	if years <= 0 {
+		return 0, errors.New("the option is expired")
+	}
+	if spot <= 0 || strike <= 0 {
+		return 0, errors.New("spot and strike must be positive")
+	}
+	// The price must be between the values at zero and infinite volatility
+	discountedStrike := strike * math.Exp(-rate*years)
+	lower, upper := math.Max(spot-discountedStrike, 0), spot
+	if optionType == Put {
+		lower, upper = math.Max(discountedStrike-spot, 0), discountedStrike
+	}
+	if price <= lower || price >= upper {
+		return 0, errors.New("price is outside the arbitrage bounds of the option")
+	}
+
+	low, high := 1e-4, 5.0
+	for i := 0; i < 200 && high-low > 1e-8; i++ {
+		volatility := (low + high) / 2
+		greeks, err := BlackScholes(optionType, spot, strike, years, rate, volatility)
+		if err != nil {
+			return 0, err
+		}
+		if greeks.Value > price {
+			high = volatility
+		} else {
+			low = volatility
+		}
+	}
+	return (low + high) / 2, nil
+}
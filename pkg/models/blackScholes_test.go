@@ -0,0 +1,160 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBlackScholes(t *testing.T) {
+	tests := []struct {
+		name       string
+		optionType OptionType
+		spot       float64
+		strike     float64
+		years      float64
+		rate       float64
+		volatility float64
+		want       OptionGreeks
+	}{
+		// At the money, one year, 5% rate, 20% volatility
+		{"at the money call", Call, 100, 100, 1, 0.05, 0.2,
+			OptionGreeks{Value: 10.4506, Delta: 0.6368, Gamma: 0.018762, Theta: -6.4140 / 365, Vega: 0.37524, Rho: 0.53232}},
+		{"at the money put", Put, 100, 100, 1, 0.05, 0.2,
+			OptionGreeks{Value: 5.5735, Delta: -0.3632, Gamma: 0.018762, Theta: -1.6579 / 365, Vega: 0.37524, Rho: -0.41890}},
+		// Hull, Options, Futures and Other Derivatives, example 15.6
+		{"hull call", Call, 42, 40, 0.5, 0.1, 0.2,
+			OptionGreeks{Value: 4.7594, Delta: 0.7791, Gamma: 0.049963, Theta: -4.5591 / 365, Vega: 0.088134, Rho: 0.13982}},
+		{"hull put", Put, 42, 40, 0.5, 0.1, 0.2,
+			OptionGreeks{Value: 0.8086, Delta: -0.2209, Gamma: 0.049963, Theta: -0.7542 / 365, Vega: 0.088134, Rho: -0.050425}},
+		// Expired, the intrinsic value with a delta of one
+		{"expired call in the money", Call, 110, 100, 0, 0.05, 0.2, OptionGreeks{Value: 10, Delta: 1}},
+		{"expired put in the money", Put, 90, 100, 0, 0.05, 0.2, OptionGreeks{Value: 10, Delta: -1}},
+		{"expired call out of the money", Call, 90, 100, 0, 0.05, 0.2, OptionGreeks{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BlackScholes(tt.optionType, tt.spot, tt.strike, tt.years, tt.rate, tt.volatility)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			checks := []struct {
+				field     string
+				got, want float64
+			}{
+				{"value", got.Value, tt.want.Value},
+				{"delta", got.Delta, tt.want.Delta},
+				{"gamma", got.Gamma, tt.want.Gamma},
+				{"theta", got.Theta, tt.want.Theta},
+				{"vega", got.Vega, tt.want.Vega},
+				{"rho", got.Rho, tt.want.Rho},
+			}
+			for _, c := range checks {
+				if math.Abs(c.got-c.want) > 1e-4 {
+					t.Errorf("%s = %v, want %v", c.field, c.got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestBlackScholesPutCallParity(t *testing.T) {
+	tests := []struct {
+		spot, strike, years, rate, volatility float64
+	}{
+		{100, 100, 1, 0.05, 0.2},
+		{42, 40, 0.5, 0.1, 0.2},
+		{50, 70, 2, 0.03, 0.45},
+		{120, 80, 0.1, 0, 0.3},
+	}
+	for _, tt := range tests {
+		call, err := BlackScholes(Call, tt.spot, tt.strike, tt.years, tt.rate, tt.volatility)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		put, err := BlackScholes(Put, tt.spot, tt.strike, tt.years, tt.rate, tt.volatility)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// C - P = S - K e^(-rT)
+		want := tt.spot - tt.strike*math.Exp(-tt.rate*tt.years)
+		if got := call.Value - put.Value; math.Abs(got-want) > 1e-9 {
+			t.Errorf("%+v: call minus put = %v, want %v", tt, got, want)
+		}
+		if got := call.Delta - put.Delta; math.Abs(got-1) > 1e-12 {
+			t.Errorf("%+v: call delta minus put delta = %v, want 1", tt, got)
+		}
+	}
+}
+
+func TestBlackScholesErrors(t *testing.T) {
+	tests := []struct {
+		name                                  string
+		spot, strike, years, rate, volatility float64
+	}{
+		{"zero spot", 0, 100, 1, 0.05, 0.2},
+		{"negative strike", 100, -1, 1, 0.05, 0.2},
+		{"zero volatility", 100, 100, 1, 0.05, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := BlackScholes(Call, tt.spot, tt.strike, tt.years, tt.rate, tt.volatility); err == nil {
+				t.Fatalf("expected an error, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestImpliedVolatility(t *testing.T) {
+	tests := []struct {
+		name       string
+		optionType OptionType
+		spot       float64
+		strike     float64
+		years      float64
+		rate       float64
+		volatility float64
+	}{
+		{"at the money call", Call, 100, 100, 1, 0.05, 0.2},
+		{"at the money put", Put, 100, 100, 1, 0.05, 0.2},
+		{"out of the money call", Call, 100, 130, 0.25, 0.02, 0.55},
+		{"in the money put", Put, 80, 100, 2, 0.04, 0.15},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			greeks, err := BlackScholes(tt.optionType, tt.spot, tt.strike, tt.years, tt.rate, tt.volatility)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := ImpliedVolatility(tt.optionType, greeks.Value, tt.spot, tt.strike, tt.years, tt.rate)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if math.Abs(got-tt.volatility) > 1e-6 {
+				t.Errorf("got %v, want %v", got, tt.volatility)
+			}
+		})
+	}
+}
+
+func TestImpliedVolatilityErrors(t *testing.T) {
+	// Spot 100, strike 95 and 5% for a year, the discounted strike is 90.37
+	tests := []struct {
+		name       string
+		optionType OptionType
+		price      float64
+		years      float64
+	}{
+		{"expired", Call, 10, 0},
+		{"call below spot minus discounted strike", Call, 9, 1},
+		{"call at the spot", Call, 100, 1},
+		{"put above the discounted strike", Put, 91, 1},
+		{"put of zero", Put, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := ImpliedVolatility(tt.optionType, tt.price, 100, 95, tt.years, 0.05); err == nil {
+				t.Fatalf("expected an error, got %v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// bondPriceBase is the original face value the prices and the cash flows of a bond are quoted on
+const bondPriceBase = 100
+
+/*
+ * Terms of a sovereign or corporate bond, the positions on it are matched by Symbol
+ */
+type BondIssue struct {
+	gorm.Model
+	Symbol    string         `json:"symbol" gorm:"uniqueIndex"` // Financial asset symbol
+	Issuer    string         `json:"issuer"`
+	Currency  string         `json:"currency"`   // Currency of the payments
+	IssueDate time.Time      `json:"issue_date"` // Start of the first coupon period
+	CashFlows []BondCashFlow `json:"cash_flows"` // Payment schedule, oldest first
+}
+
+/*
+ * Payment of a bond, per 100 of original face
+ */
+type BondCashFlow struct {
+	gorm.Model
+	BondIssueID  uint      `json:"bond_issue_id"` // Foreign key to BondIssue
+	PaymentDate  time.Time `json:"payment_date"`
+	Coupon       float64   `json:"coupon"`       // Interest paid
+	Amortization float64   `json:"amortization"` // Principal repaid
+}
+
+// Total returns the cash paid on the date
+func (f BondCashFlow) Total() float64 {
+	return f.Coupon + f.Amortization
+}
+
+func NewBondIssue(symb string, issuer string, currency string, issueDate time.Time, cashFlows []BondCashFlow) (*BondIssue, error) {
+	if symb == "" {
+		return nil, errors.New("symbol cannot be empty")
+	}
+	if issueDate.IsZero() {
+		return nil, errors.New("issue date cannot be empty")
+	}
+	if len(cashFlows) == 0 {
+		return nil, errors.New("the schedule needs at least one cash flow")
+	}
+	flows := append([]BondCashFlow(nil), cashFlows...)
+	sort.Slice(flows, func(i, j int) bool { return flows[i].PaymentDate.Before(flows[j].PaymentDate) })
+	principal := 0.0
+	for i, f := range flows {
+		if !f.PaymentDate.After(issueDate) {
+			return nil, fmt.Errorf("cash flow %d is not paid after the issue date", i)
+		}
+		if i > 0 && f.PaymentDate.Equal(flows[i-1].PaymentDate) {
+			return nil, fmt.Errorf("cash flow %d repeats a payment date", i)
+		}
+		if f.Coupon < 0 || f.Amortization < 0 {
+			return nil, fmt.Errorf("cash flow %d has a negative amount", i)
+		}
+		principal += f.Amortization
+	}
+	if math.Abs(principal-bondPriceBase) > 1e-6 {
+		return nil, fmt.Errorf("the amortizations must repay %d of face, they repay %.4f", bondPriceBase, principal)
+	}
+	return &BondIssue{Symbol: strings.ToUpper(symb), Issuer: issuer, Currency: strings.ToUpper(currency), IssueDate: issueDate, CashFlows: flows}, nil
+}
+
+// Maturity returns the date of the last payment
+func (b *BondIssue) Maturity() time.Time {
+	return b.CashFlows[len(b.CashFlows)-1].PaymentDate
+}
+
+// ResidualValue returns the principal outstanding at the settlement, per 100 of original face
+func (b *BondIssue) ResidualValue(settlement time.Time) float64 {
+	residual := float64(bondPriceBase)
+	for _, f := range b.CashFlows {
+		if !f.PaymentDate.After(settlement) {
+			residual -= f.Amortization
+		}
+	}
+	return math.Max(residual, 0)
+}
+
+// AccruedInterest returns the part of the next coupon earned since the previous payment, linear in the days
+func (b *BondIssue) AccruedInterest(settlement time.Time) float64 {
+	periodStart := b.IssueDate
+	for _, f := range b.CashFlows {
+		if !f.PaymentDate.After(settlement) {
+			periodStart = f.PaymentDate
+			continue
+		}
+		if settlement.Before(periodStart) {
+			return 0
+		}
+		return f.Coupon * settlement.Sub(periodStart).Hours() / f.PaymentDate.Sub(periodStart).Hours()
+	}
+	return 0
+}
+
+// yearsBetween returns the time between the dates in years of 365 days
+func yearsBetween(from time.Time, to time.Time) float64 {
+	return to.Sub(from).Hours() / 24 / 365
+}
+
+/*
+ * Yield and risk of a bond at a price
+ */
+type BondAnalytics struct {
+	Symbol           string    `json:"symbol"`
+	Settlement       time.Time `json:"settlement"`
+	Maturity         time.Time `json:"maturity"`
+	Price            float64   `json:"price"`             // Clean price per 100 of original face
+	AccruedInterest  float64   `json:"accrued_interest"`  // Coupon earned since the previous payment
+	DirtyPrice       float64   `json:"dirty_price"`       // Price plus accrued interest, what the buyer pays
+	ResidualValue    float64   `json:"residual_value"`    // Principal outstanding
+	TechnicalValue   float64   `json:"technical_value"`   // Residual value plus accrued interest
+	Parity           float64   `json:"parity"`            // Dirty price over technical value, in percentage
+	YieldToMaturity  float64   `json:"yield_to_maturity"` // Effective annual yield of the remaining flows, in percentage
+	CurrentYield     float64   `json:"current_yield"`     // Coupons of the next 12 months over the price, in percentage
+	MacaulayDuration float64   `json:"macaulay_duration"` // Average years to the flows weighted by their present value
+	ModifiedDuration float64   `json:"modified_duration"` // Percentage change of the price per yield point
+	Convexity        float64   `json:"convexity"`         // Change of the duration with the yield
+}
+
+// presentValue discounts the flows after the settlement at the effective annual yield
+func (b *BondIssue) presentValue(settlement time.Time, yield float64) float64 {
+	value := 0.0
+	for _, f := range b.CashFlows {
+		if f.PaymentDate.After(settlement) {
+			value += f.Total() / math.Pow(1+yield, yearsBetween(settlement, f.PaymentDate))
+		}
+	}
+	return value
+}
+
+// Analyze solves the yield to maturity at the clean price and derives the duration and convexity from it
+func (b *BondIssue) Analyze(price float64, settlement time.Time) (*BondAnalytics, error) {
+	if price <= 0 {
+		return nil, errors.New("price must be positive")
+	}
+	if len(b.CashFlows) == 0 || !b.Maturity().After(settlement) {
+		return nil, errors.New("the bond has no payments left after the settlement")
+	}
+	analytics := &BondAnalytics{
+		Symbol:          b.Symbol,
+		Settlement:      settlement,
+		Maturity:        b.Maturity(),
+		Price:           price,
+		AccruedInterest: b.AccruedInterest(settlement),
+		ResidualValue:   b.ResidualValue(settlement),
+	}
+	analytics.DirtyPrice = price + analytics.AccruedInterest
+	analytics.TechnicalValue = analytics.ResidualValue + analytics.AccruedInterest
+	if analytics.TechnicalValue > 0 {
+		analytics.Parity = analytics.DirtyPrice / analytics.TechnicalValue * 100
+	}
+
+	// The present value falls as the yield grows, bisect between -99% and 10000%
+	low, high := -0.99, 100.0
+	if b.presentValue(settlement, low) < analytics.DirtyPrice || b.presentValue(settlement, high) > analytics.DirtyPrice {
+		return nil, errors.New("no yield discounts the remaining flows to the price")
+	}
+	for i := 0; i < 200 && high-low > 1e-10; i++ {
+		yield := (low + high) / 2
+		if b.presentValue(settlement, yield) > analytics.DirtyPrice {
+			low = yield
+		} else {
+			high = yield
+		}
+	}
+	yield := (low + high) / 2
+	analytics.YieldToMaturity = yield * 100
+
+	yearAhead := settlement.AddDate(1, 0, 0)
+	coupons := 0.0
+	macaulay := 0.0
+	convexity := 0.0
+	for _, f := range b.CashFlows {
+		if !f.PaymentDate.After(settlement) {
+			continue
+		}
+		if !f.PaymentDate.After(yearAhead) {
+			coupons += f.Coupon
+		}
+		years := yearsBetween(settlement, f.PaymentDate)
+		discounted := f.Total() / math.Pow(1+yield, years)
+		macaulay += years * discounted
+		convexity += years * (years + 1) * discounted / ((1 + yield) * (1 + yield))
+	}
+	analytics.CurrentYield = coupons / price * 100
+	analytics.MacaulayDuration = macaulay / analytics.DirtyPrice
+	analytics.ModifiedDuration = analytics.MacaulayDuration / (1 + yield)
+	analytics.Convexity = convexity / analytics.DirtyPrice
+	return analytics, nil
+}
+
+/*
+ * Value of a position on a bond, with the accrued interest it would be sold with
+ */
+type BondPositionValue struct {
+	BondAnalytics
+	Quantity      decimal.Decimal `json:"quantity"`       // Original face held, negative when short
+	MarketValue   float64         `json:"market_value"`   // Face at the clean price
+	AccruedAmount float64         `json:"accrued_amount"` // Interest earned by the face since the previous payment
+	DirtyValue    float64         `json:"dirty_value"`    // Market value plus accrued interest
+	UnrealizedPnL float64         `json:"unrealized_pnl"` // Clean price against the entry price
+}
+
+// ValuePosition values the face of the position at the analytics price
+func (a *BondAnalytics) ValuePosition(p *Position) *BondPositionValue {
+	quantity := p.SignedQuantity()
+	value := &BondPositionValue{
+		BondAnalytics: *a,
+		Quantity:      quantity,
+		MarketValue:   p.Notional(a.Price, quantity),
+		AccruedAmount: p.Notional(a.AccruedInterest, quantity),
+		UnrealizedPnL: p.PnL(a.Price, p.Quantity),
+	}
+	value.DirtyValue = value.MarketValue + value.AccruedAmount
+	return value
+}
@@ -0,0 +1,216 @@
+package models
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// day returns midnight UTC of the date
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+// bulletBond pays the annual coupon on every January 1st after 2021 and the face on the last one.
+// The years between the payments have 365 days.
+func bulletBond(t *testing.T, coupon float64, years int) *BondIssue {
+	t.Helper()
+	flows := make([]BondCashFlow, years)
+	for i := range flows {
+		flows[i] = BondCashFlow{PaymentDate: day(2022+i, time.January, 1), Coupon: coupon}
+	}
+	flows[years-1].Amortization = 100
+	bond, err := NewBondIssue("bond", "Issuer", "usd", day(2021, time.January, 1), flows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return bond
+}
+
+func TestNewBondIssue(t *testing.T) {
+	issue := day(2021, time.January, 1)
+	tests := []struct {
+		name      string
+		issueDate time.Time
+		flows     []BondCashFlow
+		wantErr   bool
+	}{
+		{"bullet", issue, []BondCashFlow{{PaymentDate: day(2022, time.January, 1), Coupon: 5, Amortization: 100}}, false},
+		{"amortizing out of order", issue, []BondCashFlow{
+			{PaymentDate: day(2023, time.January, 1), Coupon: 2.5, Amortization: 50},
+			{PaymentDate: day(2022, time.January, 1), Coupon: 5, Amortization: 50},
+		}, false},
+		{"no issue date", time.Time{}, []BondCashFlow{{PaymentDate: day(2022, time.January, 1), Amortization: 100}}, true},
+		{"no cash flows", issue, nil, true},
+		{"paid on the issue date", issue, []BondCashFlow{{PaymentDate: issue, Amortization: 100}}, true},
+		{"repeated date", issue, []BondCashFlow{
+			{PaymentDate: day(2022, time.January, 1), Amortization: 50},
+			{PaymentDate: day(2022, time.January, 1), Amortization: 50},
+		}, true},
+		{"negative coupon", issue, []BondCashFlow{{PaymentDate: day(2022, time.January, 1), Coupon: -1, Amortization: 100}}, true},
+		{"does not repay the face", issue, []BondCashFlow{{PaymentDate: day(2022, time.January, 1), Amortization: 90}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bond, err := NewBondIssue("bond", "Issuer", "usd", tt.issueDate, tt.flows)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", bond)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if bond.Symbol != "BOND" || bond.Currency != "USD" {
+				t.Errorf("symbol and currency = %s %s, want BOND USD", bond.Symbol, bond.Currency)
+			}
+			for i := 1; i < len(bond.CashFlows); i++ {
+				if !bond.CashFlows[i].PaymentDate.After(bond.CashFlows[i-1].PaymentDate) {
+					t.Errorf("cash flows are not sorted by payment date")
+				}
+			}
+		})
+	}
+}
+
+func TestBondResidualValueAndAccruedInterest(t *testing.T) {
+	bond, err := NewBondIssue("amort", "Issuer", "USD", day(2021, time.January, 1), []BondCashFlow{
+		{PaymentDate: day(2022, time.January, 1), Coupon: 10, Amortization: 50},
+		{PaymentDate: day(2023, time.January, 1), Coupon: 5, Amortization: 50},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		name         string
+		settlement   time.Time
+		wantResidual float64
+		wantAccrued  float64
+	}{
+		{"issue date", day(2021, time.January, 1), 100, 0},
+		// 181 of the 365 days of the first coupon
+		{"first period", day(2021, time.July, 1), 100, 10 * 181.0 / 365},
+		{"first payment date", day(2022, time.January, 1), 50, 0},
+		// 90 of the 365 days of the second coupon, on the residual 50
+		{"second period", day(2022, time.April, 1), 50, 5 * 90.0 / 365},
+		{"matured", day(2023, time.January, 1), 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bond.ResidualValue(tt.settlement); math.Abs(got-tt.wantResidual) > 1e-9 {
+				t.Errorf("residual value = %v, want %v", got, tt.wantResidual)
+			}
+			if got := bond.AccruedInterest(tt.settlement); math.Abs(got-tt.wantAccrued) > 1e-9 {
+				t.Errorf("accrued interest = %v, want %v", got, tt.wantAccrued)
+			}
+		})
+	}
+}
+
+func TestBondAnalyze(t *testing.T) {
+	settlement := day(2021, time.January, 1)
+	tests := []struct {
+		name          string
+		bond          *BondIssue
+		price         float64
+		wantYield     float64
+		wantMacaulay  float64
+		wantModified  float64
+		wantConvexity float64
+		wantCurrent   float64
+	}{
+		// At par the yield is the coupon: D = (10/1.1 + 2*110/1.1^2) / 100, C = (1*2*10/1.1 + 2*3*110/1.1^2) / 1.1^2 / 100
+		{"par bond", bulletBond(t, 10, 2), 100, 10, 1.909091, 1.735537, 4.658152, 10},
+		// 100 / 1.05^2 = 90.702948, the duration of a zero is its maturity
+		{"zero coupon", bulletBond(t, 0, 2), 100 / 1.1025, 5, 2, 2 / 1.05, 6 / 1.1025, 0},
+		// A discount bond yields more than its coupon: 5/1.08 + 5/1.08^2 + 105/1.08^3 = 92.268709
+		{"discount bond", bulletBond(t, 5, 3), 92.268709, 8, 2.853190, 2.641843, 9.618906, 5 / 92.268709 * 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.bond.Analyze(tt.price, settlement)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			checks := []struct {
+				field     string
+				got, want float64
+			}{
+				{"yield to maturity", got.YieldToMaturity, tt.wantYield},
+				{"macaulay duration", got.MacaulayDuration, tt.wantMacaulay},
+				{"modified duration", got.ModifiedDuration, tt.wantModified},
+				{"convexity", got.Convexity, tt.wantConvexity},
+				{"current yield", got.CurrentYield, tt.wantCurrent},
+				{"parity", got.Parity, tt.price},
+			}
+			for _, c := range checks {
+				if math.Abs(c.got-c.want) > 1e-5 {
+					t.Errorf("%s = %v, want %v", c.field, c.got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestBondAnalyzeErrors(t *testing.T) {
+	bond := bulletBond(t, 10, 2)
+	tests := []struct {
+		name       string
+		price      float64
+		settlement time.Time
+	}{
+		{"zero price", 0, day(2021, time.January, 1)},
+		{"matured", 100, day(2023, time.January, 1)},
+		// At -99% the flows are worth 10/0.01 + 110/0.01^2 = 1101000, a higher price has no yield
+		{"price above the lowest yield", 1e7, day(2021, time.January, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := bond.Analyze(tt.price, tt.settlement); err == nil {
+				t.Fatalf("expected an error, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestBondValuePosition(t *testing.T) {
+	bond := bulletBond(t, 10, 2)
+	// 181 of the 365 days of the first coupon accrued
+	analytics, err := bond.Analyze(98, day(2021, time.July, 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	accrued := 10 * 181.0 / 365
+	tests := []struct {
+		name         string
+		positionType PositionType
+		wantMarket   float64
+		wantAccrued  float64
+		wantPnL      float64
+	}{
+		// 5000 of face bought at 95
+		{"long", Bought, 4900, 50 * accrued, 150},
+		{"short", Shorted, -4900, -50 * accrued, -150},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			position := &Position{Symbol: "BOND", MarketType: Bond, EntryPrice: 95, Quantity: decimal.New(5000, 0), PositionType: tt.positionType}
+			got := analytics.ValuePosition(position)
+			if math.Abs(got.MarketValue-tt.wantMarket) > 1e-9 {
+				t.Errorf("market value = %v, want %v", got.MarketValue, tt.wantMarket)
+			}
+			if math.Abs(got.AccruedAmount-tt.wantAccrued) > 1e-9 {
+				t.Errorf("accrued amount = %v, want %v", got.AccruedAmount, tt.wantAccrued)
+			}
+			if math.Abs(got.DirtyValue-(tt.wantMarket+tt.wantAccrued)) > 1e-9 {
+				t.Errorf("dirty value = %v, want %v", got.DirtyValue, tt.wantMarket+tt.wantAccrued)
+			}
+			if math.Abs(got.UnrealizedPnL-tt.wantPnL) > 1e-9 {
+				t.Errorf("unrealized P&L = %v, want %v", got.UnrealizedPnL, tt.wantPnL)
+			}
+		})
+	}
+}
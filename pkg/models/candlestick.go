@@ -0,0 +1,184 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// CandlestickPattern is a pattern that completes on the bar with the given timestamp
+type CandlestickPattern struct {
+	Name      string `json:"name"`      // Name of the pattern
+	Bias      string `json:"bias"`      // Bullish, Bearish or Neutral
+	Bars      int    `json:"bars"`      // Number of bars of the pattern
+	Index     int    `json:"index"`     // Position of the last bar of the pattern
+	TimeStamp int64  `json:"timestamp"` // Timestamp of the last bar of the pattern
+}
+
+type Candlestick struct {
+	symbol    string
+	Patterns  []CandlestickPattern
+	TrendType TrendType
+	Result    string
+}
+
+func NewCandlestick(symbol string) (*Candlestick, error) {
+	if symbol == "" {
+		return nil, errors.New("symbol cannot be empty")
+	}
+	return &Candlestick{symbol: symbol}, nil
+}
+
+func (i *Candlestick) SetIndex(indexes *Indexes) *Indexes {
+	indexes.Candlestick = *i
+	return indexes
+}
+
+type candle struct {
+	open, high, low, close float64
+}
+
+func (c candle) body() float64        { return math.Abs(c.close - c.open) }
+func (c candle) span() float64        { return c.high - c.low }
+func (c candle) upperShadow() float64 { return c.high - math.Max(c.open, c.close) }
+func (c candle) lowerShadow() float64 { return math.Min(c.open, c.close) - c.low }
+func (c candle) bullish() bool        { return c.close > c.open }
+func (c candle) bearish() bool        { return c.close < c.open }
+func (c candle) midpoint() float64    { return (c.open + c.close) / 2 }
+func (c candle) bodyTop() float64     { return math.Max(c.open, c.close) }
+func (c candle) bodyBottom() float64  { return math.Min(c.open, c.close) }
+
+// detectPatterns returns the patterns completed on bar i, averageBody tells a long body from a short one
+func (cs *Candlestick) detectPatterns(candles []candle, i int, averageBody float64) []CandlestickPattern {
+	var patterns []CandlestickPattern
+	add := func(name string, bias string, bars int) {
+		patterns = append(patterns, CandlestickPattern{Name: name, Bias: bias, Bars: bars, Index: i})
+	}
+	c := candles[i]
+	if c.span() <= 0 {
+		return nil
+	}
+	isDoji := c.body() <= 0.1*c.span()
+	if isDoji {
+		add("Doji", "Neutral", 1)
+	}
+	// A hammer needs a previous decline
+	if i >= 4 && !isDoji && c.lowerShadow() >= 2*c.body() && c.upperShadow() <= 0.5*c.body() && candles[i-1].close < candles[i-4].close {
+		add("Hammer", "Bullish", 1)
+	}
+	if i < 1 {
+		return patterns
+	}
+	p := candles[i-1]
+	if p.bearish() && c.bullish() && c.open <= p.close && c.close >= p.open && c.body() > p.body() {
+		add("Bullish Engulfing", "Bullish", 2)
+	}
+	if p.bullish() && c.bearish() && c.open >= p.close && c.close <= p.open && c.body() > p.body() {
+		add("Bearish Engulfing", "Bearish", 2)
+	}
+	if p.bearish() && p.body() >= averageBody && c.bullish() && c.bodyTop() < p.bodyTop() && c.bodyBottom() > p.bodyBottom() {
+		add("Bullish Harami", "Bullish", 2)
+	}
+	if p.bullish() && p.body() >= averageBody && c.bearish() && c.bodyTop() < p.bodyTop() && c.bodyBottom() > p.bodyBottom() {
+		add("Bearish Harami", "Bearish", 2)
+	}
+	if i < 2 {
+		return patterns
+	}
+	f := candles[i-2]
+	if f.bearish() && f.body() >= averageBody && p.body() <= 0.3*f.body() && p.bodyTop() <= f.close && c.bullish() && c.close > f.midpoint() {
+		add("Morning Star", "Bullish", 3)
+	}
+	if f.bullish() && f.body() >= averageBody && p.body() <= 0.3*f.body() && p.bodyBottom() >= f.close && c.bearish() && c.close < f.midpoint() {
+		add("Evening Star", "Bearish", 3)
+	}
+	soldiers, crows := true, true
+	for _, j := range []int{i - 2, i - 1, i} {
+		k := candles[j]
+		soldiers = soldiers && k.bullish() && k.upperShadow() <= 0.3*k.body()
+		crows = crows && k.bearish() && k.lowerShadow() <= 0.3*k.body()
+		if j > i-2 {
+			prev := candles[j-1]
+			soldiers = soldiers && k.close > prev.close && k.open >= prev.bodyBottom() && k.open <= prev.bodyTop()
+			crows = crows && k.close < prev.close && k.open >= prev.bodyBottom() && k.open <= prev.bodyTop()
+		}
+	}
+	if soldiers {
+		add("Three White Soldiers", "Bullish", 3)
+	}
+	if crows {
+		add("Three Black Crows", "Bearish", 3)
+	}
+	return patterns
+}
+
+// calculate detects the patterns completed on the last 'lookback' bars
+func (cs *Candlestick) calculate(marketDataList []BasicMarketData, lookback int) error {
+	if len(marketDataList) < 3 {
+		return fmt.Errorf("not enough data to detect candlestick patterns")
+	}
+	candles := make([]candle, len(marketDataList))
+	for i, d := range marketDataList {
+		candles[i] = candle{open: d.Open, high: d.High, low: d.Low, close: d.Close}
+	}
+	start := max(len(candles)-lookback, 0)
+	for i := start; i < len(candles); i++ {
+		if candles[i].open <= 0 {
+			return fmt.Errorf("the bars do not include the open price")
+		}
+	}
+
+	// Average body of the 10 bars before the lookback, of the lookback itself when no bar precedes it
+	sumBody := 0.0
+	from, to := max(start-10, 0), start
+	if from == to {
+		to = len(candles)
+	}
+	for i := from; i < to; i++ {
+		sumBody += candles[i].body()
+	}
+	averageBody := sumBody / float64(to-from)
+
+	cs.Patterns = nil
+	for i := start; i < len(candles); i++ {
+		for _, pattern := range cs.detectPatterns(candles, i, averageBody) {
+			pattern.TimeStamp = marketDataList[i].TimeStamp
+			cs.Patterns = append(cs.Patterns, pattern)
+		}
+	}
+	return nil
+}
+
+// Analyze detects the patterns on the latest bars and reports their bias
+func (cs *Candlestick) Analyze(marketDataList []BasicMarketData) error {
+	if err := cs.calculate(marketDataList, 5); err != nil {
+		cs.TrendType = None
+		cs.Result = fmt.Sprintf("It is not possible to detect candlestick patterns due to: %s", err)
+		return fmt.Errorf("It is not possible to detect candlestick patterns due to: %s", err)
+	}
+	if len(cs.Patterns) == 0 {
+		cs.TrendType = Neutral
+		cs.Result = "No candlestick patterns detected on the latest bars."
+		return nil
+	}
+	bullish, bearish := 0, 0
+	for _, pattern := range cs.Patterns {
+		switch pattern.Bias {
+		case "Bullish":
+			bullish++
+		case "Bearish":
+			bearish++
+		}
+	}
+	latest := cs.Patterns[len(cs.Patterns)-1]
+	switch {
+	case bullish > bearish:
+		cs.TrendType = Potential_Uptrend
+	case bearish > bullish:
+		cs.TrendType = Potential_Downtrend
+	default:
+		cs.TrendType = Neutral
+	}
+	cs.Result = fmt.Sprintf("%d pattern(s) detected on the latest bars (%d bullish, %d bearish), the most recent is %s.", len(cs.Patterns), bullish, bearish, latest.Name)
+	return nil
+}
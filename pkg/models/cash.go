@@ -0,0 +1,113 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CashTransactionType int
+
+// Define constants representing the enumerator values
+const (
+	Deposit CashTransactionType = iota
+	Withdrawal
+	BuyDebit
+	SellCredit
+	FeeDebit
+	IncomeCredit
+)
+
+func (t CashTransactionType) String() string {
+	switch t {
+	case Deposit:
+		return "Deposit"
+	case Withdrawal:
+		return "Withdrawal"
+	case BuyDebit:
+		return "Buy"
+	case SellCredit:
+		return "Sell"
+	case FeeDebit:
+		return "Fee"
+	case IncomeCredit:
+		return "Income"
+	default:
+		return "Unknown CashTransactionType"
+	}
+}
+
+// IsDebit reports whether the transaction takes cash out of the account
+func (t CashTransactionType) IsDebit() bool {
+	return t == Withdrawal || t == BuyDebit || t == FeeDebit
+}
+
+// ErrInsufficientCash is returned when a debit exceeds the balance of an account without overdraft
+var ErrInsufficientCash = errors.New("insufficient cash")
+
+/*
+ * Cash of one currency
+ */
+type CashAccount struct {
+	gorm.Model
+	Currency       string  `json:"currency" gorm:"uniqueIndex"` // ISO 4217 code
+	Balance        float64 `json:"balance"`
+	AllowOverdraft bool    `json:"allow_overdraft"` // Buys and fees may leave a negative balance, withdrawals never
+}
+
+func NewCashAccount(currency string, allowOverdraft bool) (*CashAccount, error) {
+	if len(currency) != 3 {
+		return nil, errors.New("currency must be a 3 letter code")
+	}
+	return &CashAccount{Currency: strings.ToUpper(currency), AllowOverdraft: allowOverdraft}, nil
+}
+
+/*
+ * Movement of an account, the amount is negative for debits
+ */
+type CashTransaction struct {
+	gorm.Model
+	CashAccountID   uint                `json:"cash_account_id"` // Foreign key to CashAccount
+	Currency        string              `json:"currency"`
+	TransactionType CashTransactionType `json:"transaction_type"`
+	Amount          float64             `json:"amount"`
+	BalanceAfter    float64             `json:"balance_after"`
+	PositionID      *uint               `json:"position_id,omitempty"` // Position of the buy, sell or fee
+	IncomeID        *uint               `json:"income_id,omitempty"`   // Income credited
+	Description     string              `json:"description"`
+	OccurredAt      time.Time           `json:"occurred_at"`
+}
+
+// CanDebit reports whether the account can pay the amount with a transaction of the type
+func (a *CashAccount) CanDebit(transactionType CashTransactionType, amount float64) bool {
+	if a.AllowOverdraft && transactionType != Withdrawal {
+		return true
+	}
+	return a.Balance-amount >= -1e-9
+}
+
+// Apply moves the amount in or out of the account and returns the transaction to record
+func (a *CashAccount) Apply(transactionType CashTransactionType, amount float64, description string) (*CashTransaction, error) {
+	if amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+	signed := amount
+	if transactionType.IsDebit() {
+		if !a.CanDebit(transactionType, amount) {
+			return nil, ErrInsufficientCash
+		}
+		signed = -amount
+	}
+	a.Balance += signed
+	return &CashTransaction{
+		CashAccountID:   a.ID,
+		Currency:        a.Currency,
+		TransactionType: transactionType,
+		Amount:          signed,
+		BalanceAfter:    a.Balance,
+		Description:     description,
+		OccurredAt:      time.Now(),
+	}, nil
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+type CMF struct {
+	symbol    string
+	LatestCMF float64
+	TrendType TrendType
+	Result    string
+}
+
+func NewCMF(symbol string) (*CMF, error) {
+	if symbol == "" {
+		return nil, errors.New("symbol cannot be empty")
+	}
+	return &CMF{symbol: symbol}, nil
+}
+
+func (i *CMF) SetIndex(indexes *Indexes) *Indexes {
+	indexes.CMF = *i
+	return indexes
+}
+
+// calculateCMF calculates the Chaikin Money Flow (CMF) for a given period
+func (cmf *CMF) calculateCMF(marketDataList []BasicMarketData, period int) ([]float64, error) {
+	if len(marketDataList) < period {
+		return nil, fmt.Errorf("not enough data to calculate CMF for the given period")
+	}
+
+	// Calculate the Money Flow Volume of each bar
+	flowVolumes := make([]float64, len(marketDataList))
+	for i, d := range marketDataList {
+		if d.High == d.Low {
+			continue
+		}
+		multiplier := ((d.Close - d.Low) - (d.High - d.Close)) / (d.High - d.Low)
+		flowVolumes[i] = multiplier * float64(d.Volume)
+	}
+
+	cmfs := make([]float64, len(marketDataList)-period+1)
+	for i := period - 1; i < len(marketDataList); i++ {
+		sumFlowVolume, sumVolume := 0.0, 0.0
+		for j := i - period + 1; j <= i; j++ {
+			sumFlowVolume += flowVolumes[j]
+			sumVolume += float64(marketDataList[j].Volume)
+		}
+		if sumVolume > 0 {
+			cmfs[i-period+1] = sumFlowVolume / sumVolume
+		}
+	}
+
+	return cmfs, nil
+}
+
+// Analyze analyzes the CMF value to determine accumulation or distribution
+func (cmf *CMF) Analyze(marketDataList []BasicMarketData) error {
+	cmfValues, err := cmf.calculateCMF(marketDataList, 20)
+	if err != nil {
+		cmf.TrendType = None
+		cmf.Result = fmt.Sprintf("It is not possible to calculate CMF due to: %s", err)
+		return fmt.Errorf("It is not possible to calculate CMF due to: %s", err)
+	}
+	cmf.LatestCMF = cmfValues[len(cmfValues)-1]
+
+	switch {
+	case cmf.LatestCMF > 0.05:
+		cmf.TrendType = Accumulation
+		cmf.Result = fmt.Sprintf("CMF is %.2f, indicating buying pressure (accumulation).", cmf.LatestCMF)
+	case cmf.LatestCMF < -0.05:
+		cmf.TrendType = Distribution
+		cmf.Result = fmt.Sprintf("CMF is %.2f, indicating selling pressure (distribution).", cmf.LatestCMF)
+	default:
+		cmf.TrendType = Neutral
+		cmf.Result = fmt.Sprintf("CMF is %.2f, indicating no clear buying or selling pressure.", cmf.LatestCMF)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type CorporateActionType int
+
+// Define constants representing the enumerator values
+const (
+	Split CorporateActionType = iota
+	CashDividend
+)
+
+func (t CorporateActionType) String() string {
+	switch t {
+	case Split:
+		return "Split"
+	case CashDividend:
+		return "Cash Dividend"
+	default:
+		return "Unknown CorporateActionType"
+	}
+}
+
+/*
+ * A split or cash dividend, applied to the open positions once its ex-date is reached
+ */
+type CorporateAction struct {
+	gorm.Model
+	Symbol          string              `json:"symbol"`             // Financial asset symbol
+	ActionType      CorporateActionType `json:"action_type"`        // Split or cash dividend
+	ExDate          time.Time           `json:"ex_date"`            // First session traded without the right
+	Ratio           float64             `json:"ratio"`              // Shares after the split for each share before, 4 for 4-for-1, 0.1 for 1-for-10
+	Amount          float64             `json:"amount"`             // Cash dividend per share
+	CashInLieuPrice float64             `json:"cash_in_lieu_price"` // Price after the split paid for the fractions of share, the quote if zero
+	Applied         bool                `json:"applied"`            // The positions and the income were already updated
+	AppliedAt       time.Time           `json:"applied_at"`         // Zero until it is applied
+}
+
+func NewCorporateAction(symb string, actionType CorporateActionType, exDate time.Time, ratio float64, amount float64, cashInLieuPrice float64) (*CorporateAction, error) {
+	if symb == "" {
+		return nil, errors.New("symbol cannot be empty")
+	}
+	if exDate.IsZero() {
+		return nil, errors.New("ex-date cannot be empty")
+	}
+	switch actionType {
+	case Split:
+		if ratio <= 0 || ratio == 1 {
+			return nil, errors.New("split ratio must be positive and different from 1")
+		}
+	case CashDividend:
+		if amount <= 0 {
+			return nil, errors.New("dividend amount must be positive")
+		}
+	default:
+		return nil, errors.New("unknown corporate action type")
+	}
+	if cashInLieuPrice < 0 {
+		return nil, errors.New("cash in lieu price cannot be negative")
+	}
+	return &CorporateAction{Symbol: symb, ActionType: actionType, ExDate: exDate, Ratio: ratio, Amount: amount, CashInLieuPrice: cashInLieuPrice}, nil
+}
+
+// IsHeldAt reports whether the position was open before the ex-date, so the corporate action applies to it.
+// A short owes the dividends of the borrowed shares.
+func (p *Position) IsHeldAt(exDate time.Time) bool {
+	return p.IsOpen() && p.EntryTime.Before(exDate)
+}
+
+// ApplySplit multiplies the quantity by the ratio keeping the cost basis, and rescales the price levels
+// of the stop. A position that allows fractions keeps the fraction of share the split leaves, rounded to
+// the precision of the quantities. Otherwise quantities are whole shares and the broker pays the fraction
+// at inLieuPrice, a price after the split: the fraction takes its part of the cost, the difference is
+// added to the balance as realized P&L and ApplySplit returns the fraction paid.
+func (p *Position) ApplySplit(ratio float64, inLieuPrice float64) (decimal.Decimal, error) {
+	split := p.Quantity.Mul(decimal.NewFromFloat(ratio)).Round(quantityPlaces)
+	quantity, inLieu := split, decimal.Zero
+	if !p.AllowsFractions() {
+		quantity = split.Floor()
+		inLieu = split.Sub(quantity)
+	}
+	if !quantity.IsPositive() {
+		return inLieu, errors.New("the split leaves the position without shares")
+	}
+	if inLieu.IsPositive() && inLieuPrice <= 0 {
+		return inLieu, errors.New("the split leaves a fraction of share and there is no cash in lieu price")
+	}
+	// The cost per share after the split is the same for the shares kept and for the fraction paid
+	p.EntryPrice = p.EntryPrice * QuantityFloat(p.Quantity) / QuantityFloat(split)
+	p.Balance += p.PnL(inLieuPrice, inLieu)
+	p.Quantity = quantity
+	p.StopPrice /= ratio
+	p.HighestPrice /= ratio
+	p.StopATR /= ratio
+	if p.StopType == FixedStop {
+		p.StopValue /= ratio
+	}
+	return inLieu, nil
+}
+
+// splitGapTolerance is how far the ex-date gap can be from the split ratio to consider the bars unadjusted
+const splitGapTolerance = 0.25
+
+// AdjustBarsForSplits divides the prices before each split by its ratio, and multiplies the volume.
+// The provider usually delivers split adjusted bars, so a split is only applied when the gap between
+// the last close before the ex-date and the first open on it matches the ratio.
+func AdjustBarsForSplits(marketDataList []BasicMarketData, actions []CorporateAction) []BasicMarketData {
+	adjusted := make([]BasicMarketData, len(marketDataList))
+	copy(adjusted, marketDataList)
+	for _, action := range actions {
+		if action.ActionType != Split || action.Ratio <= 0 {
+			continue
+		}
+		k := 0
+		for k < len(adjusted) && adjusted[k].TimeStamp < action.ExDate.Unix() {
+			k++
+		}
+		if k == 0 || k == len(adjusted) || adjusted[k].Open <= 0 {
+			continue
+		}
+		gap := adjusted[k-1].Close / adjusted[k].Open
+		if math.Abs(gap/action.Ratio-1) > splitGapTolerance {
+			continue
+		}
+		for i := 0; i < k; i++ {
+			adjusted[i].Open /= action.Ratio
+			adjusted[i].High /= action.Ratio
+			adjusted[i].Low /= action.Ratio
+			adjusted[i].Close /= action.Ratio
+			adjusted[i].Volume = int64(float64(adjusted[i].Volume) * action.Ratio)
+		}
+	}
+	return adjusted
+}
@@ -0,0 +1,149 @@
+package models
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestApplySplit(t *testing.T) {
+	// The fractions are paid at 22 a share after the split
+	const inLieuPrice = 22
+	tests := []struct {
+		name         string
+		marketType   MarketType
+		positionType PositionType
+		fractional   bool
+		quantity     string
+		ratio        float64
+		want         string
+		wantInLieu   string
+		wantBalance  float64
+	}{
+		{"two for one", Equity, Bought, false, "10", 2, "20", "0", 0},
+		// 7 * 1.5 = 10.5 at a cost of 20, the half share is paid 22
+		{"three for two, whole shares", Equity, Bought, false, "7", 1.5, "10", "0.5", 1},
+		// 10 / 3 = 3.33333333 at a cost of 300 / 3.33333333
+		{"one for three reverse split", Equity, Bought, false, "10", 1.0 / 3, "3", "0.33333333", 0.33333333 * (22 - 300/3.33333333)},
+		{"fractional shares keep the fraction", Equity, Bought, true, "2.5", 1.5, "3.75", "0", 0},
+		{"whole quantity with fractional shares", Equity, Bought, true, "7", 1.5, "10.5", "0", 0},
+		{"crypto keeps the fraction", Crypto, Bought, false, "2", 1.0 / 3, "0.66666667", "0", 0},
+		// Half a contract of 100 shares
+		{"options are whole contracts", Option, Bought, true, "5", 1.5, "7", "0.5", 100},
+		// The short buys back the half share above its cost
+		{"short in whole shares", Equity, Shorted, false, "7", 1.5, "10", "0.5", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quantity, _ := decimal.NewFromString(tt.quantity)
+			position := &Position{Symbol: "ABC", MarketType: tt.marketType, FractionalShares: tt.fractional, EntryPrice: 30, Quantity: quantity, PositionType: tt.positionType,
+				StopType: FixedStop, StopValue: 24, StopPrice: 24, HighestPrice: 36}
+			if tt.marketType == Option {
+				position.Option = OptionContract{Underlying: "ABC", Strike: 30, Multiplier: 100}
+			}
+			cost := position.Notional(position.EntryPrice, quantity)
+			inLieu, err := position.ApplySplit(tt.ratio, inLieuPrice)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want, _ := decimal.NewFromString(tt.want)
+			if !position.Quantity.Equal(want) {
+				t.Errorf("quantity = %s, want %s", position.Quantity, tt.want)
+			}
+			wantInLieu, _ := decimal.NewFromString(tt.wantInLieu)
+			if !inLieu.Equal(wantInLieu) {
+				t.Errorf("cash in lieu = %s shares, want %s", inLieu, tt.wantInLieu)
+			}
+			if math.Abs(position.Balance-tt.wantBalance) > 1e-6 {
+				t.Errorf("realized P&L = %v, want %v", position.Balance, tt.wantBalance)
+			}
+			// The cost basis is split between the shares held and the fraction paid
+			kept := position.Notional(position.EntryPrice, position.Quantity)
+			paid := position.Notional(position.EntryPrice, inLieu)
+			if math.Abs(kept+paid-cost) > 1e-6 {
+				t.Errorf("cost basis = %v held and %v paid, want %v", kept, paid, cost)
+			}
+			if math.Abs(position.StopPrice-24/tt.ratio) > 1e-9 || math.Abs(position.StopValue-24/tt.ratio) > 1e-9 || math.Abs(position.HighestPrice-36/tt.ratio) > 1e-9 {
+				t.Errorf("stop levels = %v %v %v, want them divided by %v", position.StopPrice, position.StopValue, position.HighestPrice, tt.ratio)
+			}
+		})
+	}
+}
+
+func TestApplySplitErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		quantity    int64
+		ratio       float64
+		inLieuPrice float64
+	}{
+		{"without shares", 2, 0.25, 22},
+		{"fraction without a cash in lieu price", 7, 1.5, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			position := &Position{Symbol: "ABC", MarketType: Equity, EntryPrice: 30, Quantity: decimal.New(tt.quantity, 0), PositionType: Bought}
+			if _, err := position.ApplySplit(tt.ratio, tt.inLieuPrice); err == nil {
+				t.Fatalf("expected an error, got a quantity of %s", position.Quantity)
+			}
+		})
+	}
+}
+
+func TestIsHeldAt(t *testing.T) {
+	exDate := time.Date(2024, time.June, 3, 0, 0, 0, 0, time.UTC)
+	before := exDate.AddDate(0, 0, -10)
+	tests := []struct {
+		name         string
+		positionType PositionType
+		quantity     int64
+		entryTime    time.Time
+		want         bool
+	}{
+		{"long bought before", Bought, 10, before, true},
+		{"short opened before", Shorted, 10, before, true},
+		{"bought on the ex-date", Bought, 10, exDate, false},
+		{"sold", Sold, 0, before, false},
+		{"covered", Covered, 0, before, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			position := &Position{PositionType: tt.positionType, Quantity: decimal.New(tt.quantity, 0), EntryTime: tt.entryTime}
+			if got := position.IsHeldAt(exDate); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllowsFractions(t *testing.T) {
+	tests := []struct {
+		name       string
+		symbol     string
+		marketType MarketType
+		fractional bool
+		quantity   string
+		want       bool
+	}{
+		{"whole shares", "AAPL", Equity, false, "10", false},
+		{"fractional shares", "AAPL", Equity, true, "10", true},
+		{"options are whole contracts", "AAPL300118C00200000", Option, true, "1", false},
+		{"crypto", "BTC-USD", Crypto, false, "0.5", true},
+		{"fx", "EURUSD=X", FX, false, "1000", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quantity, _ := decimal.NewFromString(tt.quantity)
+			position := &Position{Symbol: tt.symbol, MarketType: tt.marketType, FractionalShares: tt.fractional, Quantity: quantity}
+			if got := position.AllowsFractions(); got != tt.want {
+				t.Errorf("allows fractions = %v, want %v", got, tt.want)
+			}
+			// Half a unit is only a valid quantity when fractions are allowed
+			if err := position.ValidateQuantity(decimal.New(5, -1)); (err == nil) != tt.want {
+				t.Errorf("validate half a unit = %v, want allowed %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"strings"
+)
+
+/*
+ * Currencies of a crypto or FX symbol, its price is the units of Quote paid for one unit of Base
+ */
+type CurrencyPair struct {
+	Base  string `json:"base"`
+	Quote string `json:"quote"`
+}
+
+func (p CurrencyPair) String() string {
+	return p.Base + "/" + p.Quote
+}
+
+// ParseCurrencyPair reads the pair of a provider symbol: BTC-USD, EURUSD=X, or JPY=X which quotes USD/JPY
+func ParseCurrencyPair(symbol string) (CurrencyPair, bool) {
+	symbol = strings.ToUpper(symbol)
+	if pair, found := strings.CutSuffix(symbol, "=X"); found {
+		switch len(pair) {
+		case 6:
+			return CurrencyPair{Base: pair[:3], Quote: pair[3:]}, true
+		case 3:
+			return CurrencyPair{Base: "USD", Quote: pair}, true
+		default:
+			return CurrencyPair{}, false
+		}
+	}
+	base, quote, found := strings.Cut(symbol, "-")
+	if !found || len(base) < 2 || len(quote) < 3 || strings.Contains(quote, "-") {
+		return CurrencyPair{}, false
+	}
+	return CurrencyPair{Base: base, Quote: quote}, true
+}
+
+// IsCurrencyPair reports whether the position is on a crypto or FX pair
+func (p *Position) IsCurrencyPair() bool {
+	return p.MarketType == Crypto || p.MarketType == FX
+}
@@ -0,0 +1,205 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+type DivergenceType int
+
+// Define constants representing the enumerator values
+const (
+	RegularBullish DivergenceType = iota
+	RegularBearish
+	HiddenBullish
+	HiddenBearish
+)
+
+func (t DivergenceType) String() string {
+	switch t {
+	case RegularBullish:
+		return "Regular Bullish"
+	case RegularBearish:
+		return "Regular Bearish"
+	case HiddenBullish:
+		return "Hidden Bullish"
+	case HiddenBearish:
+		return "Hidden Bearish"
+	default:
+		return "Unknown DivergenceType"
+	}
+}
+
+func (t DivergenceType) Bullish() bool {
+	return t == RegularBullish || t == HiddenBullish
+}
+
+// DivergenceSignal compares the last two price swings against an indicator on the same bars
+type DivergenceSignal struct {
+	Indicator      string         `json:"indicator"`       // RSI, MACD Histogram or OBV
+	DivergenceType DivergenceType `json:"divergence_type"` // Regular or hidden, bullish or bearish
+	Description    string         `json:"description"`     // DivergenceType as text
+	StartIndex     int            `json:"start_index"`     // Bar of the first swing
+	EndIndex       int            `json:"end_index"`       // Bar of the second swing
+	StartTime      int64          `json:"start_time"`      // Timestamp of the first swing
+	EndTime        int64          `json:"end_time"`        // Timestamp of the second swing
+	Strength       float64        `json:"strength"`        // From 0 to 1, size of the price and indicator moves
+}
+
+type Divergence struct {
+	symbol      string
+	Divergences []DivergenceSignal
+	TrendType   TrendType
+	Result      string
+}
+
+func NewDivergence(symbol string) (*Divergence, error) {
+	if symbol == "" {
+		return nil, errors.New("symbol cannot be empty")
+	}
+	return &Divergence{symbol: symbol}, nil
+}
+
+func (i *Divergence) SetIndex(indexes *Indexes) *Indexes {
+	indexes.Divergence = *i
+	return indexes
+}
+
+// seriesRange returns the difference between the highest and the lowest valid values
+func seriesRange(values []float64) float64 {
+	high, low := math.Inf(-1), math.Inf(1)
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			high = math.Max(high, v)
+			low = math.Min(low, v)
+		}
+	}
+	if math.IsInf(high, 0) {
+		return 0
+	}
+	return high - low
+}
+
+// compareSwings checks the last two swings of the prices against the indicator on the same bars
+func (d *Divergence) compareSwings(name string, marketDataList []BasicMarketData, prices []float64, indicator []float64, pivots []int, high bool) *DivergenceSignal {
+	if len(pivots) < 2 {
+		return nil
+	}
+	first, second := pivots[len(pivots)-2], pivots[len(pivots)-1]
+	if math.IsNaN(indicator[first]) || math.IsNaN(indicator[second]) {
+		return nil
+	}
+	priceMove := prices[second] - prices[first]
+	indicatorMove := indicator[second] - indicator[first]
+	var divergenceType DivergenceType
+	switch {
+	case high && priceMove > 0 && indicatorMove < 0:
+		divergenceType = RegularBearish
+	case high && priceMove < 0 && indicatorMove > 0:
+		divergenceType = HiddenBearish
+	case !high && priceMove < 0 && indicatorMove > 0:
+		divergenceType = RegularBullish
+	case !high && priceMove > 0 && indicatorMove < 0:
+		divergenceType = HiddenBullish
+	default:
+		return nil
+	}
+	strength := 0.0
+	if priceRange, indicatorRange := seriesRange(prices), seriesRange(indicator); priceRange > 0 && indicatorRange > 0 {
+		strength = math.Min(1, (math.Abs(priceMove)/priceRange+math.Abs(indicatorMove)/indicatorRange)/2)
+	}
+	return &DivergenceSignal{
+		Indicator:      name,
+		DivergenceType: divergenceType,
+		Description:    divergenceType.String(),
+		StartIndex:     first,
+		EndIndex:       second,
+		StartTime:      marketDataList[first].TimeStamp,
+		EndTime:        marketDataList[second].TimeStamp,
+		Strength:       strength,
+	}
+}
+
+// calculate compares the price swings against the RSI, the MACD histogram and the OBV
+func (d *Divergence) calculate(marketDataList []BasicMarketData, window int) error {
+	rsi, err := (&RSI{}).calculateRSI(marketDataList, 14)
+	if err != nil {
+		return fmt.Errorf("Error calculating RSI: %v", err)
+	}
+	histogram, err := calculateMACDHistogram(marketDataList)
+	if err != nil {
+		return fmt.Errorf("Error calculating MACD: %v", err)
+	}
+	obv, err := (&OBV{}).calculateOBV(marketDataList)
+	if err != nil {
+		return fmt.Errorf("Error calculating OBV: %v", err)
+	}
+	indicators := []struct {
+		name   string
+		values []float64
+	}{
+		{"RSI", alignSeries(rsi, len(marketDataList))},
+		{"MACD Histogram", alignSeries(histogram, len(marketDataList))},
+		{"OBV", obv},
+	}
+
+	_, highs, lows, _ := ExtractMarketData(marketDataList)
+	highPivots := findPivots(highs, window, true)
+	lowPivots := findPivots(lows, window, false)
+	d.Divergences = nil
+	for _, indicator := range indicators {
+		if signal := d.compareSwings(indicator.name, marketDataList, highs, indicator.values, highPivots, true); signal != nil {
+			d.Divergences = append(d.Divergences, *signal)
+		}
+		if signal := d.compareSwings(indicator.name, marketDataList, lows, indicator.values, lowPivots, false); signal != nil {
+			d.Divergences = append(d.Divergences, *signal)
+		}
+	}
+	return nil
+}
+
+// Analyze detects the divergences and summarizes their bias
+func (d *Divergence) Analyze(marketDataList []BasicMarketData) error {
+	if err := d.calculate(marketDataList, 3); err != nil {
+		d.TrendType = None
+		d.Result = fmt.Sprintf("It is not possible to detect divergences due to: %s", err)
+		return fmt.Errorf("It is not possible to detect divergences due to: %s", err)
+	}
+	if len(d.Divergences) == 0 {
+		d.TrendType = Neutral
+		d.Result = "No divergences detected between price and RSI, MACD histogram or OBV."
+		return nil
+	}
+	bullish, bearish := 0.0, 0.0
+	for _, signal := range d.Divergences {
+		if signal.DivergenceType.Bullish() {
+			bullish += signal.Strength
+		} else {
+			bearish += signal.Strength
+		}
+	}
+	switch {
+	case bullish > bearish:
+		d.TrendType = Potential_Uptrend
+		d.Result = fmt.Sprintf("%d divergence(s) detected with a bullish bias, the most recent is a %s divergence on %s.", len(d.Divergences), d.latest().Description, d.latest().Indicator)
+	case bearish > bullish:
+		d.TrendType = Potential_Downtrend
+		d.Result = fmt.Sprintf("%d divergence(s) detected with a bearish bias, the most recent is a %s divergence on %s.", len(d.Divergences), d.latest().Description, d.latest().Indicator)
+	default:
+		d.TrendType = Neutral
+		d.Result = fmt.Sprintf("%d divergence(s) detected without a clear bias.", len(d.Divergences))
+	}
+	return nil
+}
+
+// latest returns the divergence that ends on the most recent bar
+func (d *Divergence) latest() DivergenceSignal {
+	latest := d.Divergences[0]
+	for _, signal := range d.Divergences[1:] {
+		if signal.EndIndex > latest.EndIndex {
+			latest = signal
+		}
+	}
+	return latest
+}
@@ -0,0 +1,702 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+/*
+ * Expression is a small rule language evaluated over BasicMarketData series,
+ * e.g. "close > sma(50) and rsi(14) crosses above 30".
+ *
+ * Every expression evaluates to a series aligned with the bars. Indicator values
+ * that can not be computed for a bar (warm-up period) are NaN, booleans are 1 or 0.
+ */
+type Expression struct {
+	source string
+	root   exprNode
+}
+
+// ParseError describes a syntax error and the position where it was found
+type ParseError struct {
+	Position int
+	Message  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("parse error at position %d: %s", e.Position, e.Message)
+}
+
+// ParseExpression parses the source of a rule into an Expression
+func ParseExpression(source string) (*Expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, &ParseError{Position: tok.pos, Message: fmt.Sprintf("unexpected %s", tok)}
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Evaluate returns the value of the expression for every bar
+func (e *Expression) Evaluate(marketDataList []BasicMarketData) ([]float64, error) {
+	if len(marketDataList) == 0 {
+		return nil, fmt.Errorf("not enough data to evaluate %q", e.source)
+	}
+	return e.root.eval(marketDataList)
+}
+
+// Signals returns, for every bar, whether the expression holds
+func (e *Expression) Signals(marketDataList []BasicMarketData) ([]bool, error) {
+	values, err := e.Evaluate(marketDataList)
+	if err != nil {
+		return nil, err
+	}
+	signals := make([]bool, len(values))
+	for i, v := range values {
+		signals[i] = truthy(v)
+	}
+	return signals, nil
+}
+
+// Latest returns the value of the expression on the last bar
+func (e *Expression) Latest(marketDataList []BasicMarketData) (float64, error) {
+	values, err := e.Evaluate(marketDataList)
+	if err != nil {
+		return 0, err
+	}
+	return values[len(values)-1], nil
+}
+
+// Matches checks if the expression holds on the last bar
+func (e *Expression) Matches(marketDataList []BasicMarketData) (bool, error) {
+	value, err := e.Latest(marketDataList)
+	if err != nil {
+		return false, err
+	}
+	return truthy(value), nil
+}
+
+func truthy(v float64) bool {
+	return !math.IsNaN(v) && v != 0
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// alignSeries places the values at the end of a series of size n, the rest is NaN
+func alignSeries(values []float64, n int) []float64 {
+	series := make([]float64, n)
+	offset := n - len(values)
+	for i := range series {
+		if i < offset {
+			series[i] = math.NaN()
+		} else {
+			series[i] = values[i-offset]
+		}
+	}
+	return series
+}
+
+/*
+ * Lexer
+ */
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: strings.ToLower(string(runes[start:i])), pos: start})
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+		case strings.ContainsRune("<>=!", r):
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				tokens = append(tokens, token{kind: tokenOperator, text: string(runes[i : i+2]), pos: i})
+				i += 2
+			} else if r == '<' || r == '>' {
+				tokens = append(tokens, token{kind: tokenOperator, text: string(r), pos: i})
+				i++
+			} else {
+				return nil, &ParseError{Position: i, Message: fmt.Sprintf("unexpected character %q", r)}
+			}
+		case strings.ContainsRune("+-*/", r):
+			tokens = append(tokens, token{kind: tokenOperator, text: string(r), pos: i})
+			i++
+		default:
+			return nil, &ParseError{Position: i, Message: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+	tokens = append(tokens, token{kind: tokenEOF, pos: len(runes)})
+	return tokens, nil
+}
+
+/*
+ * Parser
+ *
+ *   or         := and ("or" and)*
+ *   and        := not ("and" not)*
+ *   not        := "not" not | comparison
+ *   comparison := additive (("<" | "<=" | ">" | ">=" | "==" | "!=" | "crosses above" | "crosses below") additive)?
+ *   additive   := term (("+" | "-") term)*
+ *   term       := unary (("*" | "/") unary)*
+ *   unary      := "-" unary | primary
+ *   primary    := number | series | function "(" args ")" | "(" or ")"
+ */
+
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *exprParser) isKeyword(word string) bool {
+	tok := p.peek()
+	return tok.kind == tokenIdent && tok.text == word
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "or", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("and") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "and", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseNot() (exprNode, error) {
+	if p.isKeyword("not") {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (exprNode, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	tok := p.peek()
+	var op string
+	switch {
+	case tok.kind == tokenOperator && strings.Contains("< <= > >= == !=", tok.text):
+		op = tok.text
+		p.next()
+	case p.isKeyword("crosses"):
+		p.next()
+		direction := p.next()
+		if direction.kind != tokenIdent || (direction.text != "above" && direction.text != "below") {
+			return nil, &ParseError{Position: direction.pos, Message: fmt.Sprintf("expected 'above' or 'below' after 'crosses', got %s", direction)}
+		}
+		op = "crosses " + direction.text
+	default:
+		return left, nil
+	}
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(op, "crosses") {
+		return &crossNode{above: op == "crosses above", left: left, right: right}, nil
+	}
+	return &binaryNode{op: op, left: left, right: right}, nil
+}
+
+func (p *exprParser) parseAdditive() (exprNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for tok := p.peek(); tok.kind == tokenOperator && (tok.text == "+" || tok.text == "-"); tok = p.peek() {
+		p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: tok.text, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseTerm() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for tok := p.peek(); tok.kind == tokenOperator && (tok.text == "*" || tok.text == "/"); tok = p.peek() {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: tok.text, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if tok := p.peek(); tok.kind == tokenOperator && tok.text == "-" {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryNode{op: "-", left: &numberNode{value: 0}, right: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber:
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, &ParseError{Position: tok.pos, Message: fmt.Sprintf("invalid number %s", tok)}
+		}
+		return &numberNode{value: value}, nil
+	case tokenLParen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, &ParseError{Position: closing.pos, Message: fmt.Sprintf("expected ')', got %s", closing)}
+		}
+		return node, nil
+	case tokenIdent:
+		if p.peek().kind == tokenLParen {
+			return p.parseCall(tok)
+		}
+		if _, ok := expressionSeries[tok.text]; ok {
+			return &seriesNode{name: tok.text}, nil
+		}
+		if _, ok := expressionFunctions[tok.text]; ok {
+			return p.parseCall(tok)
+		}
+		return nil, &ParseError{Position: tok.pos, Message: fmt.Sprintf("unknown identifier %s", tok)}
+	default:
+		return nil, &ParseError{Position: tok.pos, Message: fmt.Sprintf("unexpected %s", tok)}
+	}
+}
+
+// parseCall parses the arguments of a function, which must be numeric constants
+func (p *exprParser) parseCall(name token) (exprNode, error) {
+	function, ok := expressionFunctions[name.text]
+	if !ok {
+		return nil, &ParseError{Position: name.pos, Message: fmt.Sprintf("unknown function %s", name)}
+	}
+	var args []float64
+	if p.peek().kind == tokenLParen {
+		p.next()
+		for p.peek().kind != tokenRParen {
+			if len(args) > 0 {
+				if comma := p.next(); comma.kind != tokenComma {
+					return nil, &ParseError{Position: comma.pos, Message: fmt.Sprintf("expected ',' or ')', got %s", comma)}
+				}
+			}
+			arg := p.next()
+			if arg.kind != tokenNumber {
+				return nil, &ParseError{Position: arg.pos, Message: fmt.Sprintf("arguments of %s must be numbers, got %q", name.text, arg.text)}
+			}
+			value, err := strconv.ParseFloat(arg.text, 64)
+			if err != nil || value <= 0 || value != math.Trunc(value) {
+				return nil, &ParseError{Position: arg.pos, Message: fmt.Sprintf("arguments of %s must be positive integers, got %q", name.text, arg.text)}
+			}
+			args = append(args, value)
+		}
+		p.next()
+	}
+	if len(args) < function.minArgs || len(args) > function.maxArgs {
+		return nil, &ParseError{Position: name.pos, Message: fmt.Sprintf("%s expects %s, got %d", name.text, function.arity(), len(args))}
+	}
+	// Missing arguments take their default values
+	for i := len(args); i < len(function.defaults); i++ {
+		args = append(args, function.defaults[i])
+	}
+	intArgs := make([]int, len(args))
+	for i, a := range args {
+		intArgs[i] = int(a)
+	}
+	return &callNode{name: name.text, args: intArgs, function: function}, nil
+}
+
+/*
+ * Evaluation
+ */
+
+type exprNode interface {
+	eval(marketDataList []BasicMarketData) ([]float64, error)
+}
+
+type numberNode struct {
+	value float64
+}
+
+func (n *numberNode) eval(marketDataList []BasicMarketData) ([]float64, error) {
+	series := make([]float64, len(marketDataList))
+	for i := range series {
+		series[i] = n.value
+	}
+	return series, nil
+}
+
+type seriesNode struct {
+	name string
+}
+
+func (n *seriesNode) eval(marketDataList []BasicMarketData) ([]float64, error) {
+	series := make([]float64, len(marketDataList))
+	extract := expressionSeries[n.name]
+	for i, data := range marketDataList {
+		series[i] = extract(data)
+	}
+	return series, nil
+}
+
+type callNode struct {
+	name     string
+	args     []int
+	function expressionFunction
+}
+
+func (n *callNode) eval(marketDataList []BasicMarketData) ([]float64, error) {
+	values, err := n.function.calculate(marketDataList, n.args)
+	if err != nil {
+		return nil, fmt.Errorf("%s%v: %w", n.name, n.args, err)
+	}
+	return alignSeries(values, len(marketDataList)), nil
+}
+
+type notNode struct {
+	operand exprNode
+}
+
+func (n *notNode) eval(marketDataList []BasicMarketData) ([]float64, error) {
+	values, err := n.operand.eval(marketDataList)
+	if err != nil {
+		return nil, err
+	}
+	series := make([]float64, len(values))
+	for i, v := range values {
+		if math.IsNaN(v) {
+			series[i] = math.NaN()
+		} else {
+			series[i] = boolValue(v == 0)
+		}
+	}
+	return series, nil
+}
+
+type binaryNode struct {
+	op          string
+	left, right exprNode
+}
+
+func (n *binaryNode) eval(marketDataList []BasicMarketData) ([]float64, error) {
+	left, err := n.left.eval(marketDataList)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(marketDataList)
+	if err != nil {
+		return nil, err
+	}
+	series := make([]float64, len(left))
+	for i := range series {
+		a, b := left[i], right[i]
+		switch n.op {
+		case "and":
+			series[i] = boolValue(truthy(a) && truthy(b))
+			continue
+		case "or":
+			series[i] = boolValue(truthy(a) || truthy(b))
+			continue
+		}
+		if math.IsNaN(a) || math.IsNaN(b) {
+			series[i] = math.NaN()
+			continue
+		}
+		switch n.op {
+		case "+":
+			series[i] = a + b
+		case "-":
+			series[i] = a - b
+		case "*":
+			series[i] = a * b
+		case "/":
+			if b == 0 {
+				series[i] = math.NaN()
+			} else {
+				series[i] = a / b
+			}
+		case "<":
+			series[i] = boolValue(a < b)
+		case "<=":
+			series[i] = boolValue(a <= b)
+		case ">":
+			series[i] = boolValue(a > b)
+		case ">=":
+			series[i] = boolValue(a >= b)
+		case "==":
+			series[i] = boolValue(a == b)
+		case "!=":
+			series[i] = boolValue(a != b)
+		}
+	}
+	return series, nil
+}
+
+type crossNode struct {
+	above       bool
+	left, right exprNode
+}
+
+func (n *crossNode) eval(marketDataList []BasicMarketData) ([]float64, error) {
+	left, err := n.left.eval(marketDataList)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(marketDataList)
+	if err != nil {
+		return nil, err
+	}
+	series := make([]float64, len(left))
+	series[0] = math.NaN()
+	for i := 1; i < len(series); i++ {
+		if math.IsNaN(left[i]) || math.IsNaN(right[i]) || math.IsNaN(left[i-1]) || math.IsNaN(right[i-1]) {
+			series[i] = math.NaN()
+		} else if n.above {
+			series[i] = boolValue(left[i] > right[i] && left[i-1] <= right[i-1])
+		} else {
+			series[i] = boolValue(left[i] < right[i] && left[i-1] >= right[i-1])
+		}
+	}
+	return series, nil
+}
+
+/*
+ * Series and functions available in expressions
+ */
+
+var expressionSeries = map[string]func(BasicMarketData) float64{
+	"close":  func(d BasicMarketData) float64 { return d.Close },
+	"high":   func(d BasicMarketData) float64 { return d.High },
+	"low":    func(d BasicMarketData) float64 { return d.Low },
+	"volume": func(d BasicMarketData) float64 { return float64(d.Volume) },
+}
+
+// expressionFunction computes an indicator, its values are aligned to the last bar
+type expressionFunction struct {
+	minArgs   int
+	maxArgs   int
+	defaults  []float64
+	calculate func(marketDataList []BasicMarketData, args []int) ([]float64, error)
+}
+
+func (f expressionFunction) arity() string {
+	if f.minArgs == f.maxArgs {
+		return fmt.Sprintf("%d argument(s)", f.minArgs)
+	}
+	return fmt.Sprintf("%d to %d arguments", f.minArgs, f.maxArgs)
+}
+
+var expressionFunctions = map[string]expressionFunction{
+	"sma": {minArgs: 1, maxArgs: 1, calculate: func(marketDataList []BasicMarketData, args []int) ([]float64, error) {
+		sma := &SMA{}
+		if len(marketDataList) < args[0] {
+			return nil, fmt.Errorf("not enough data to calculate SMA%d", args[0])
+		}
+		values := make([]float64, 0, len(marketDataList)-args[0]+1)
+		for i := args[0]; i <= len(marketDataList); i++ {
+			value, err := sma.calculateSMAN(marketDataList[:i], args[0])
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	}},
+	"ema": {minArgs: 1, maxArgs: 1, calculate: func(marketDataList []BasicMarketData, args []int) ([]float64, error) {
+		return (&EMA{}).CalculateEMA(marketDataList, args[0])
+	}},
+	"rsi": {minArgs: 0, maxArgs: 1, defaults: []float64{14}, calculate: func(marketDataList []BasicMarketData, args []int) ([]float64, error) {
+		return (&RSI{}).calculateRSI(marketDataList, args[0])
+	}},
+	"atr": {minArgs: 0, maxArgs: 1, defaults: []float64{14}, calculate: func(marketDataList []BasicMarketData, args []int) ([]float64, error) {
+		return (&ATR{}).calculateATR(marketDataList, args[0])
+	}},
+	"adx": {minArgs: 0, maxArgs: 1, defaults: []float64{14}, calculate: func(marketDataList []BasicMarketData, args []int) ([]float64, error) {
+		if len(marketDataList) < 2*args[0]+2 {
+			return nil, fmt.Errorf("not enough data to calculate ADX for the given period")
+		}
+		adx, err := (&ADX{}).calculateADX(marketDataList, args[0])
+		if err != nil {
+			return nil, err
+		}
+		// Only the values after the DX smoothing window are complete
+		return adx[args[0]+1:], nil
+	}},
+	"cci": {minArgs: 0, maxArgs: 1, defaults: []float64{20}, calculate: func(marketDataList []BasicMarketData, args []int) ([]float64, error) {
+		return (&CCI{}).calculateCCI(marketDataList, args[0])
+	}},
+	"momentum": {minArgs: 0, maxArgs: 1, defaults: []float64{10}, calculate: func(marketDataList []BasicMarketData, args []int) ([]float64, error) {
+		return (&Momentum{}).calculateMomentum(marketDataList, args[0])
+	}},
+	"mfi": {minArgs: 0, maxArgs: 1, defaults: []float64{14}, calculate: func(marketDataList []BasicMarketData, args []int) ([]float64, error) {
+		return (&MFI{}).calculateMFI(marketDataList, args[0])
+	}},
+	"williams_r": {minArgs: 0, maxArgs: 1, defaults: []float64{14}, calculate: func(marketDataList []BasicMarketData, args []int) ([]float64, error) {
+		return (&WilliamsR{}).calculateWilliamsR(marketDataList, args[0])
+	}},
+	"cmf": {minArgs: 0, maxArgs: 1, defaults: []float64{20}, calculate: func(marketDataList []BasicMarketData, args []int) ([]float64, error) {
+		return (&CMF{}).calculateCMF(marketDataList, args[0])
+	}},
+	"supertrend": {minArgs: 0, maxArgs: 2, defaults: []float64{10, 3}, calculate: func(marketDataList []BasicMarketData, args []int) ([]float64, error) {
+		line, _, err := (&Supertrend{}).calculateSupertrend(marketDataList, args[0], float64(args[1]))
+		return line, err
+	}},
+	"aroon_up": {minArgs: 0, maxArgs: 1, defaults: []float64{25}, calculate: func(marketDataList []BasicMarketData, args []int) ([]float64, error) {
+		up, _, err := (&Aroon{}).calculateAroon(marketDataList, args[0])
+		return up, err
+	}},
+	"aroon_down": {minArgs: 0, maxArgs: 1, defaults: []float64{25}, calculate: func(marketDataList []BasicMarketData, args []int) ([]float64, error) {
+		_, down, err := (&Aroon{}).calculateAroon(marketDataList, args[0])
+		return down, err
+	}},
+	"trix": {minArgs: 0, maxArgs: 1, defaults: []float64{15}, calculate: func(marketDataList []BasicMarketData, args []int) ([]float64, error) {
+		trix, _, err := (&TRIX{}).calculateTRIX(marketDataList, args[0], 9)
+		return trix, err
+	}},
+	"obv": {minArgs: 0, maxArgs: 0, calculate: func(marketDataList []BasicMarketData, args []int) ([]float64, error) {
+		return (&OBV{}).calculateOBV(marketDataList)
+	}},
+	"stoch_k": {minArgs: 0, maxArgs: 1, defaults: []float64{14}, calculate: func(marketDataList []BasicMarketData, args []int) ([]float64, error) {
+		k, _, err := (&Stochastic{}).calculateStochasticOscillator(marketDataList, args[0])
+		return k, err
+	}},
+	"stoch_d": {minArgs: 0, maxArgs: 1, defaults: []float64{14}, calculate: func(marketDataList []BasicMarketData, args []int) ([]float64, error) {
+		_, d, err := (&Stochastic{}).calculateStochasticOscillator(marketDataList, args[0])
+		if err != nil {
+			return nil, err
+		}
+		// The first two values of %D are not defined
+		if len(d) < 3 {
+			return nil, fmt.Errorf("not enough data to calculate Stochastic %%D")
+		}
+		return d[2:], nil
+	}},
+	"macd": {minArgs: 0, maxArgs: 0, calculate: func(marketDataList []BasicMarketData, args []int) ([]float64, error) {
+		macdArray, _, err := calculateMACDSeries(marketDataList)
+		return macdArray, err
+	}},
+	"macd_signal": {minArgs: 0, maxArgs: 0, calculate: func(marketDataList []BasicMarketData, args []int) ([]float64, error) {
+		_, signal, err := calculateMACDSeries(marketDataList)
+		return signal, err
+	}},
+	"macd_hist": {minArgs: 0, maxArgs: 0, calculate: func(marketDataList []BasicMarketData, args []int) ([]float64, error) {
+		return calculateMACDHistogram(marketDataList)
+	}},
+}
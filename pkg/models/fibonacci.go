@@ -0,0 +1,131 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	fibonacciRetracements = []float64{0.236, 0.382, 0.5, 0.618, 0.786}
+	fibonacciExtensions   = []float64{1.272, 1.618}
+)
+
+type FibonacciLevel struct {
+	Ratio      float64 `json:"ratio"`      // Fibonacci ratio, e.g. 0.618
+	Price      float64 `json:"price"`      // Price of the level
+	Extension  bool    `json:"extension"`  // Extension when true, retracement otherwise
+	Near       bool    `json:"near"`       // The last close is within the tolerance of the level
+	Percentage string  `json:"percentage"` // Ratio as a percentage, e.g. 61.8%
+}
+
+type Fibonacci struct {
+	symbol    string
+	SwingHigh Swing
+	SwingLow  Swing
+	Upswing   bool // The low happened before the high
+	Levels    []FibonacciLevel
+	LastClose float64
+	TrendType TrendType
+	Result    string
+}
+
+func NewFibonacci(symbol string) (*Fibonacci, error) {
+	if symbol == "" {
+		return nil, errors.New("symbol cannot be empty")
+	}
+	return &Fibonacci{symbol: symbol}, nil
+}
+
+func (i *Fibonacci) SetIndex(indexes *Indexes) *Indexes {
+	indexes.Fibonacci = *i
+	return indexes
+}
+
+// calculateLevels finds the dominant swing of the data and its retracements and extensions
+func (fib *Fibonacci) calculateLevels(marketDataList []BasicMarketData, tolerance float64) error {
+	if len(marketDataList) < 2 {
+		return fmt.Errorf("not enough data to calculate Fibonacci levels")
+	}
+	high, low := 0, 0
+	for i, d := range marketDataList {
+		if d.High > marketDataList[high].High {
+			high = i
+		}
+		if d.Low < marketDataList[low].Low {
+			low = i
+		}
+	}
+	fib.SwingHigh = Swing{Index: high, Price: marketDataList[high].High, High: true, TimeStamp: marketDataList[high].TimeStamp}
+	fib.SwingLow = Swing{Index: low, Price: marketDataList[low].Low, High: false, TimeStamp: marketDataList[low].TimeStamp}
+	fib.Upswing = low < high
+	fib.LastClose = marketDataList[len(marketDataList)-1].Close
+	swingRange := fib.SwingHigh.Price - fib.SwingLow.Price
+	if swingRange <= 0 {
+		return fmt.Errorf("there is no price range to calculate Fibonacci levels")
+	}
+
+	fib.Levels = nil
+	addLevel := func(ratio float64, price float64, extension bool) {
+		near := math.Abs(fib.LastClose-price) <= price*tolerance
+		fib.Levels = append(fib.Levels, FibonacciLevel{Ratio: ratio, Price: price, Extension: extension, Near: near, Percentage: fmt.Sprintf("%.1f%%", ratio*100)})
+	}
+	for _, ratio := range fibonacciRetracements {
+		if fib.Upswing {
+			addLevel(ratio, fib.SwingHigh.Price-ratio*swingRange, false)
+		} else {
+			addLevel(ratio, fib.SwingLow.Price+ratio*swingRange, false)
+		}
+	}
+	for _, ratio := range fibonacciExtensions {
+		if fib.Upswing {
+			addLevel(ratio, fib.SwingLow.Price+ratio*swingRange, true)
+		} else {
+			addLevel(ratio, fib.SwingHigh.Price-ratio*swingRange, true)
+		}
+	}
+	return nil
+}
+
+// NearestLevel returns the level closest to the last close
+func (fib *Fibonacci) NearestLevel() *FibonacciLevel {
+	var nearest *FibonacciLevel
+	for i := range fib.Levels {
+		if nearest == nil || math.Abs(fib.Levels[i].Price-fib.LastClose) < math.Abs(nearest.Price-fib.LastClose) {
+			nearest = &fib.Levels[i]
+		}
+	}
+	return nearest
+}
+
+// Analyze calculates the levels and flags the one the price is near
+func (fib *Fibonacci) Analyze(marketDataList []BasicMarketData) error {
+	if err := fib.calculateLevels(marketDataList, 0.01); err != nil {
+		fib.TrendType = None
+		fib.Result = fmt.Sprintf("It is not possible to calculate Fibonacci levels due to: %s", err)
+		return fmt.Errorf("It is not possible to calculate Fibonacci levels due to: %s", err)
+	}
+	direction := "downswing"
+	if fib.Upswing {
+		direction = "upswing"
+	}
+	nearest := fib.NearestLevel()
+	switch {
+	case nearest.Near && !nearest.Extension && fib.Upswing:
+		fib.TrendType = Potential_Uptrend
+		fib.Result = fmt.Sprintf("Price %.2f is near the %s retracement (%.2f) of the %s from %.2f to %.2f, a possible support.", fib.LastClose, nearest.Percentage, nearest.Price, direction, fib.SwingLow.Price, fib.SwingHigh.Price)
+	case nearest.Near && !nearest.Extension:
+		fib.TrendType = Potential_Downtrend
+		fib.Result = fmt.Sprintf("Price %.2f is near the %s retracement (%.2f) of the %s from %.2f to %.2f, a possible resistance.", fib.LastClose, nearest.Percentage, nearest.Price, direction, fib.SwingHigh.Price, fib.SwingLow.Price)
+	case nearest.Near:
+		fib.TrendType = Neutral
+		fib.Result = fmt.Sprintf("Price %.2f is near the %s extension (%.2f) of the %s, a possible target.", fib.LastClose, nearest.Percentage, nearest.Price, direction)
+	case fib.Upswing:
+		fib.TrendType = Uptrend
+		fib.Result = fmt.Sprintf("Price %.2f is not near any level of the upswing from %.2f to %.2f, the nearest is %s at %.2f.", fib.LastClose, fib.SwingLow.Price, fib.SwingHigh.Price, nearest.Percentage, nearest.Price)
+	default:
+		fib.TrendType = Downtrend
+		fib.Result = fmt.Sprintf("Price %.2f is not near any level of the downswing from %.2f to %.2f, the nearest is %s at %.2f.", fib.LastClose, fib.SwingHigh.Price, fib.SwingLow.Price, nearest.Percentage, nearest.Price)
+	}
+	return nil
+}
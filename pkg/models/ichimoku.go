@@ -0,0 +1,176 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/megajandrox/go-finance-api/pkg/calendar"
+)
+
+// IchimokuPoint holds the Ichimoku lines of a bar, nil when a line is not defined for it
+type IchimokuPoint struct {
+	TimeStamp int64    `json:"timestamp"` // Estimated for the projected bars
+	Projected bool     `json:"projected"` // Future bar, only the cloud is defined
+	Tenkan    *float64 `json:"tenkan,omitempty"`
+	Kijun     *float64 `json:"kijun,omitempty"`
+	SenkouA   *float64 `json:"senkou_a,omitempty"`
+	SenkouB   *float64 `json:"senkou_b,omitempty"`
+	Chikou    *float64 `json:"chikou,omitempty"`
+}
+
+type Ichimoku struct {
+	symbol       string
+	Series       []IchimokuPoint // One point per bar plus the projected cloud
+	PriceVsCloud string          // Above, Below or Inside
+	TKCross      string          // Bullish, Bearish or None on the last bar
+	CloudColor   string          // Green or Red, for the cloud projected on the last bar
+	TrendType    TrendType
+	Result       string
+}
+
+const (
+	ichimokuTenkanPeriod  = 9
+	ichimokuKijunPeriod   = 26
+	ichimokuSenkouBPeriod = 52
+	ichimokuDisplacement  = 26
+)
+
+func NewIchimoku(symbol string) (*Ichimoku, error) {
+	if symbol == "" {
+		return nil, errors.New("symbol cannot be empty")
+	}
+	return &Ichimoku{symbol: symbol}, nil
+}
+
+func (i *Ichimoku) SetIndex(indexes *Indexes) *Indexes {
+	indexes.Ichimoku = *i
+	return indexes
+}
+
+// midpoint returns the average of the highest high and lowest low of the period ending on bar i
+func (ich *Ichimoku) midpoint(marketDataList []BasicMarketData, i int, period int) *float64 {
+	if i < period-1 {
+		return nil
+	}
+	high, low := marketDataList[i].High, marketDataList[i].Low
+	for j := i - period + 1; j <= i; j++ {
+		high = max(high, marketDataList[j].High)
+		low = min(low, marketDataList[j].Low)
+	}
+	value := (high + low) / 2
+	return &value
+}
+
+// calculate builds the series, Senkou spans are shifted forward and Chikou backward
+func (ich *Ichimoku) calculate(marketDataList []BasicMarketData) error {
+	// The cloud of the last bar is the Senkou span B of the bar displaced before it
+	n := len(marketDataList)
+	if n < ichimokuSenkouBPeriod+ichimokuDisplacement {
+		return fmt.Errorf("not enough data to calculate Ichimoku, %d bars are needed", ichimokuSenkouBPeriod+ichimokuDisplacement)
+	}
+	// The projected bars follow the sessions of the exchange, weekly and monthly bars keep the last spacing
+	exchange := calendar.ForSymbol(ich.symbol)
+	// The shortest recent gap is the bar length, the longer ones cross nights and weekends
+	spacing := marketDataList[n-1].TimeStamp - marketDataList[n-2].TimeStamp
+	for i := max(n-10, 1); i < n; i++ {
+		spacing = min(spacing, marketDataList[i].TimeStamp-marketDataList[i-1].TimeStamp)
+	}
+	series := make([]IchimokuPoint, n+ichimokuDisplacement)
+	for i := range series {
+		if i < n {
+			series[i].TimeStamp = marketDataList[i].TimeStamp
+			continue
+		}
+		if spacing < 5*24*60*60 {
+			series[i].TimeStamp = exchange.NextBar(series[i-1].TimeStamp, time.Duration(spacing)*time.Second)
+		} else {
+			series[i].TimeStamp = series[i-1].TimeStamp + spacing
+		}
+		series[i].Projected = true
+	}
+	for i := 0; i < n; i++ {
+		tenkan := ich.midpoint(marketDataList, i, ichimokuTenkanPeriod)
+		kijun := ich.midpoint(marketDataList, i, ichimokuKijunPeriod)
+		series[i].Tenkan = tenkan
+		series[i].Kijun = kijun
+		if tenkan != nil && kijun != nil {
+			senkouA := (*tenkan + *kijun) / 2
+			series[i+ichimokuDisplacement].SenkouA = &senkouA
+		}
+		series[i+ichimokuDisplacement].SenkouB = ich.midpoint(marketDataList, i, ichimokuSenkouBPeriod)
+		if i >= ichimokuDisplacement {
+			chikou := marketDataList[i].Close
+			series[i-ichimokuDisplacement].Chikou = &chikou
+		}
+	}
+	ich.Series = series
+	return nil
+}
+
+// Analyze classifies price against the cloud, the TK cross, the cloud color and the Chikou span
+func (ich *Ichimoku) Analyze(marketDataList []BasicMarketData) error {
+	if err := ich.calculate(marketDataList); err != nil {
+		ich.TrendType = None
+		ich.Result = fmt.Sprintf("It is not possible to calculate Ichimoku due to: %s", err)
+		return fmt.Errorf("It is not possible to calculate Ichimoku due to: %s", err)
+	}
+	n := len(marketDataList)
+	last, previous := ich.Series[n-1], ich.Series[n-2]
+	close := marketDataList[n-1].Close
+	score := 0
+
+	ich.PriceVsCloud = "Inside"
+	top, bottom := max(*last.SenkouA, *last.SenkouB), min(*last.SenkouA, *last.SenkouB)
+	if close > top {
+		ich.PriceVsCloud = "Above"
+		score++
+	} else if close < bottom {
+		ich.PriceVsCloud = "Below"
+		score--
+	}
+
+	ich.TKCross = "None"
+	if *last.Tenkan > *last.Kijun {
+		score++
+		if *previous.Tenkan <= *previous.Kijun {
+			ich.TKCross = "Bullish"
+		}
+	} else if *last.Tenkan < *last.Kijun {
+		score--
+		if *previous.Tenkan >= *previous.Kijun {
+			ich.TKCross = "Bearish"
+		}
+	}
+
+	projected := ich.Series[len(ich.Series)-1]
+	ich.CloudColor = "Red"
+	if *projected.SenkouA >= *projected.SenkouB {
+		ich.CloudColor = "Green"
+		score++
+	} else {
+		score--
+	}
+
+	// Chikou is the last close compared with the price 26 bars ago
+	if close > marketDataList[n-1-ichimokuDisplacement].Close {
+		score++
+	} else if close < marketDataList[n-1-ichimokuDisplacement].Close {
+		score--
+	}
+
+	switch {
+	case score >= 3:
+		ich.TrendType = Uptrend
+	case score > 0:
+		ich.TrendType = Potential_Uptrend
+	case score <= -3:
+		ich.TrendType = Downtrend
+	case score < 0:
+		ich.TrendType = Potential_Downtrend
+	default:
+		ich.TrendType = Neutral
+	}
+	ich.Result = fmt.Sprintf("Price is %s the cloud, Tenkan %.2f / Kijun %.2f (TK cross: %s), the projected cloud is %s.", map[string]string{"Above": "above", "Below": "below", "Inside": "inside"}[ich.PriceVsCloud], *last.Tenkan, *last.Kijun, ich.TKCross, ich.CloudColor)
+	return nil
+}
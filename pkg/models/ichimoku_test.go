@@ -0,0 +1,57 @@
+package models
+
+import "testing"
+
+// trendingBars builds daily bars that move by step every day, with a range of 2 around the close
+func trendingBars(n int, step float64) []BasicMarketData {
+	bars := make([]BasicMarketData, n)
+	for i := range bars {
+		close := 100 + step*float64(i)
+		bars[i] = BasicMarketData{Open: close, High: close + 1, Low: close - 1, Close: close, TimeStamp: int64(1704067200 + i*86400)}
+	}
+	return bars
+}
+
+func TestIchimokuAnalyze(t *testing.T) {
+	tests := []struct {
+		name      string
+		bars      []BasicMarketData
+		wantCloud string
+		wantTrend TrendType
+		wantErr   bool
+	}{
+		// The Senkou span B of the last bar needs 52 bars displaced by 26
+		{"without the current cloud", trendingBars(77, 1), "", None, true},
+		{"uptrend", trendingBars(78, 1), "Above", Uptrend, false},
+		{"downtrend", trendingBars(120, -0.5), "Below", Downtrend, false},
+		// Equal spans project a green cloud, the only signal of a flat price
+		{"flat", trendingBars(78, 0), "Inside", Potential_Uptrend, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ichimoku, err := NewIchimoku("AAPL")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			err = ichimoku.Analyze(tt.bars)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got price %s the cloud", ichimoku.PriceVsCloud)
+				}
+				if ichimoku.TrendType != None {
+					t.Errorf("trend = %v, want %v", ichimoku.TrendType, None)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ichimoku.PriceVsCloud != tt.wantCloud {
+				t.Errorf("price vs cloud = %s, want %s", ichimoku.PriceVsCloud, tt.wantCloud)
+			}
+			if ichimoku.TrendType != tt.wantTrend {
+				t.Errorf("trend = %v, want %v", ichimoku.TrendType, tt.wantTrend)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type IncomeType int
+
+// Define constants representing the enumerator values
+const (
+	Dividend IncomeType = iota
+	Coupon
+	Interest
+)
+
+func (t IncomeType) String() string {
+	switch t {
+	case Dividend:
+		return "Dividend"
+	case Coupon:
+		return "Coupon"
+	case Interest:
+		return "Interest"
+	default:
+		return "Unknown IncomeType"
+	}
+}
+
+/*
+ * Cash received by the holdings of an Asset
+ */
+type Income struct {
+	gorm.Model
+	AssetID           uint            `json:"asset_id"`                      // Foreign key to Asset
+	Symbol            string          `json:"symbol"`                        // Financial asset symbol
+	IncomeType        IncomeType      `json:"income_type"`                   // Source of the cash
+	CorporateActionID *uint           `json:"corporate_action_id,omitempty"` // Corporate action that generated it
+	PerShare          float64         `json:"per_share"`                     // Cash per share
+	Quantity          decimal.Decimal `json:"quantity" gorm:"type:numeric"`  // Shares held at the ex-date
+	Amount            float64         `json:"amount"`                        // Total cash
+	PaidAt            time.Time       `json:"paid_at"`
+}
+
+// NewIncome records cash received by an Asset, the amount is per share times quantity when it is not given
+func NewIncome(assetId uint, symb string, incomeType IncomeType, amount float64, perShare float64, quantity decimal.Decimal, paidAt time.Time) (*Income, error) {
+	if symb == "" {
+		return nil, errors.New("symbol cannot be empty")
+	}
+	if incomeType < Dividend || incomeType > Interest {
+		return nil, errors.New("unknown income type")
+	}
+	if amount == 0 {
+		amount = perShare * QuantityFloat(quantity)
+	}
+	if amount <= 0 {
+		return nil, errors.New("amount must be positive, or per share and quantity must be given")
+	}
+	if paidAt.IsZero() {
+		paidAt = time.Now()
+	}
+	return &Income{AssetID: assetId, Symbol: symb, IncomeType: incomeType, PerShare: perShare, Quantity: quantity, Amount: amount, PaidAt: paidAt}, nil
+}
+
+// NewDividendIncome records the dividend of the action on the shares held in the Asset
+func NewDividendIncome(assetId uint, action *CorporateAction, quantity decimal.Decimal) (*Income, error) {
+	if action.ActionType != CashDividend {
+		return nil, errors.New("the corporate action is not a cash dividend")
+	}
+	if !quantity.IsPositive() {
+		return nil, errors.New("quantity must be positive")
+	}
+	return &Income{
+		AssetID:           assetId,
+		Symbol:            action.Symbol,
+		IncomeType:        Dividend,
+		CorporateActionID: &action.ID,
+		PerShare:          action.Amount,
+		Quantity:          quantity,
+		Amount:            action.Amount * QuantityFloat(quantity),
+		PaidAt:            action.ExDate,
+	}, nil
+}
+
+/*
+ * Price and cash return of the holdings of an Asset
+ */
+type IncomeSummary struct {
+	Symbol             string             `json:"symbol"`
+	MarketPrice        float64            `json:"market_price,omitempty"`   // Last price, zero when it is not available
+	CostBasis          float64            `json:"cost_basis"`               // Cost of the open long positions
+	MarketValue        float64            `json:"market_value,omitempty"`   // Value of the open long positions at the market price
+	ShortProceeds      float64            `json:"short_proceeds"`           // Proceeds of the open shorts
+	ShortExposure      float64            `json:"short_exposure,omitempty"` // Value of the shares owed by the open shorts
+	RealizedPnL        float64            `json:"realized_pnl"`             // Balance of the sales
+	UnrealizedPnL      float64            `json:"unrealized_pnl,omitempty"` // Market value minus cost basis, plus proceeds minus exposure of the shorts
+	Income             float64            `json:"income"`                   // Every cash flow received
+	IncomeByType       map[string]float64 `json:"income_by_type"`
+	TrailingIncome     float64            `json:"trailing_income"`      // Cash received in the last 12 months
+	TotalReturn        float64            `json:"total_return"`         // Realized and unrealized P&L plus income
+	TotalReturnPercent float64            `json:"total_return_percent"` // Total return over the cost basis
+	YieldOnCost        float64            `json:"yield_on_cost"`        // Trailing income over the cost basis, in percentage
+}
+
+// SummarizeIncome adds the income of the Asset to the P&L of its positions. Without a market price the
+// open positions are valued at cost.
+func SummarizeIncome(symbol string, positions []Position, incomes []Income, marketPrice float64, now time.Time) IncomeSummary {
+	summary := IncomeSummary{Symbol: symbol, MarketPrice: marketPrice, IncomeByType: map[string]float64{}}
+	for _, p := range positions {
+		summary.RealizedPnL += p.Balance
+		if !p.IsOpen() {
+			continue
+		}
+		if p.IsShort() {
+			summary.ShortProceeds += p.Notional(p.EntryPrice, p.Quantity)
+			if marketPrice > 0 {
+				summary.ShortExposure += p.Notional(marketPrice, p.Quantity)
+			}
+			continue
+		}
+		summary.CostBasis += p.Notional(p.EntryPrice, p.Quantity)
+		if marketPrice > 0 {
+			summary.MarketValue += p.Notional(marketPrice, p.Quantity)
+		}
+	}
+	if marketPrice > 0 {
+		summary.UnrealizedPnL = summary.MarketValue - summary.CostBasis + summary.ShortProceeds - summary.ShortExposure
+	}
+	yearAgo := now.AddDate(-1, 0, 0)
+	for _, income := range incomes {
+		summary.Income += income.Amount
+		summary.IncomeByType[income.IncomeType.String()] += income.Amount
+		if income.PaidAt.After(yearAgo) && !income.PaidAt.After(now) {
+			summary.TrailingIncome += income.Amount
+		}
+	}
+	summary.TotalReturn = summary.RealizedPnL + summary.UnrealizedPnL + summary.Income
+	if summary.CostBasis > 0 {
+		summary.TotalReturnPercent = summary.TotalReturn / summary.CostBasis * 100
+		summary.YieldOnCost = summary.TrailingIncome / summary.CostBasis * 100
+	}
+	return summary
+}
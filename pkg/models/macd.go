@@ -55,6 +55,38 @@ func (macd *MACD) calculate(marketDataList []BasicMarketData) (bool, error) {
 	return true, nil
 }
 
+// calculateMACDSeries calculates the MACD and Signal line the same way calculate does, without a symbol
+func calculateMACDSeries(marketDataList []BasicMarketData) ([]float64, []float64, error) {
+	ema := &EMA{}
+	if _, err := ema.calculate(marketDataList); err != nil {
+		return nil, nil, err
+	}
+	macdArray := make([]float64, len(ema.EMA26))
+	offset := len(ema.EMA12) - len(ema.EMA26)
+	for i := range macdArray {
+		macdArray[i] = ema.EMA12[i+offset] - ema.EMA26[i]
+	}
+	signal, err := ema.CalculateEMAFromMACD(macdArray, 9)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error calculating Signal line: %v", err)
+	}
+	return macdArray, signal, nil
+}
+
+// calculateMACDHistogram calculates MACD minus Signal line, aligned to the last bar
+func calculateMACDHistogram(marketDataList []BasicMarketData) ([]float64, error) {
+	macdArray, signal, err := calculateMACDSeries(marketDataList)
+	if err != nil {
+		return nil, err
+	}
+	offset := len(macdArray) - len(signal)
+	histogram := make([]float64, len(signal))
+	for i := range histogram {
+		histogram[i] = macdArray[i+offset] - signal[i]
+	}
+	return histogram, nil
+}
+
 // analyzeMACD analyzes the MACD and Signal line for crossovers
 func (macd *MACD) Analyze(marketDataList []BasicMarketData) error {
 	var macdArray, signal []float64
@@ -70,7 +102,8 @@ func (macd *MACD) Analyze(marketDataList []BasicMarketData) error {
 	}
 	macdArray = macd.MACDArray
 	signal = macd.MACDSignal
-	if len(macdArray) == 0 || len(signal) == 0 {
+	// The crossover compares the latest values with the previous ones
+	if len(macdArray) < 2 || len(signal) < 2 {
 		macd.TrendType = Neutral
 		macd.Result = "Not enough data for MACD analysis."
 		return fmt.Errorf("Not enough data for MACD analysis.")
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+type MFI struct {
+	symbol    string
+	LatestMFI float64
+	TrendType TrendType
+	Result    string
+}
+
+func NewMFI(symbol string) (*MFI, error) {
+	if symbol == "" {
+		return nil, errors.New("symbol cannot be empty")
+	}
+	return &MFI{symbol: symbol}, nil
+}
+
+func (i *MFI) SetIndex(indexes *Indexes) *Indexes {
+	indexes.MFI = *i
+	return indexes
+}
+
+// calculateMFI calculates the Money Flow Index (MFI) for a given period
+func (mfi *MFI) calculateMFI(marketDataList []BasicMarketData, period int) ([]float64, error) {
+	if len(marketDataList) < period+1 {
+		return nil, fmt.Errorf("not enough data to calculate MFI for the given period")
+	}
+
+	// Calculate the Typical Price and the raw money flow
+	tps := make([]float64, len(marketDataList))
+	flows := make([]float64, len(marketDataList))
+	for i, d := range marketDataList {
+		tps[i] = (d.High + d.Low + d.Close) / 3
+		flows[i] = tps[i] * float64(d.Volume)
+	}
+
+	mfis := make([]float64, len(marketDataList)-period)
+	for i := period; i < len(marketDataList); i++ {
+		positiveFlow, negativeFlow := 0.0, 0.0
+		for j := i - period + 1; j <= i; j++ {
+			if tps[j] > tps[j-1] {
+				positiveFlow += flows[j]
+			} else if tps[j] < tps[j-1] {
+				negativeFlow += flows[j]
+			}
+		}
+		if positiveFlow == 0 && negativeFlow == 0 {
+			// A flat typical price has no buying or selling pressure
+			mfis[i-period] = 50
+			continue
+		}
+		if negativeFlow == 0 {
+			mfis[i-period] = 100
+			continue
+		}
+		mfis[i-period] = 100 - (100 / (1 + positiveFlow/negativeFlow))
+	}
+
+	return mfis, nil
+}
+
+// Analyze analyzes the MFI value for overbought or oversold conditions
+func (mfi *MFI) Analyze(marketDataList []BasicMarketData) error {
+	mfiValues, err := mfi.calculateMFI(marketDataList, 14)
+	if err != nil {
+		mfi.TrendType = None
+		mfi.Result = fmt.Sprintf("It is not possible to calculate MFI due to: %s", err)
+		return fmt.Errorf("It is not possible to calculate MFI due to: %s", err)
+	}
+	mfi.LatestMFI = mfiValues[len(mfiValues)-1]
+
+	switch {
+	case mfi.LatestMFI > 80:
+		mfi.TrendType = Overbought
+		mfi.Result = fmt.Sprintf("MFI is %.2f, indicating the asset is overbought.", mfi.LatestMFI)
+	case mfi.LatestMFI < 20:
+		mfi.TrendType = Oversold
+		mfi.Result = fmt.Sprintf("MFI is %.2f, indicating the asset is oversold.", mfi.LatestMFI)
+	default:
+		mfi.TrendType = Neutral
+		mfi.Result = fmt.Sprintf("MFI is %.2f, indicating normal market conditions.", mfi.LatestMFI)
+	}
+	return nil
+}
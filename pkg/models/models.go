@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
@@ -14,6 +15,8 @@ type PositionType int
 const (
 	Bought PositionType = iota
 	Sold
+	Shorted // Sold to open, the shares are borrowed
+	Covered // Bought to cover a short
 )
 
 type MarketType int
@@ -22,26 +25,67 @@ type MarketType int
 const (
 	Equity MarketType = iota
 	ETF
+	Option // Listed option, the terms are in the Option contract
+	Bond   // Bond quoted per 100 of original face, the terms are in the BondIssue of the symbol
+	Crypto // Cryptocurrency pair (BTC-USD), trades every day
+	FX     // Currency pair (EURUSD=X)
 )
 
+func (t MarketType) String() string {
+	switch t {
+	case Equity:
+		return "Equity"
+	case ETF:
+		return "ETF"
+	case Option:
+		return "Option"
+	case Bond:
+		return "Bond"
+	case Crypto:
+		return "Crypto"
+	case FX:
+		return "FX"
+	default:
+		return "Unknown MarketType"
+	}
+}
+
 /*
  * This struct is going to be persisted for the long term
  */
 type Position struct {
 	gorm.Model
-	Symbol       string       // Financial asset symbol
-	EntryPrice   float64      // Entry price
-	ExitPrice    float64      // Exit price
-	Quantity     int          // Quantity of shares/buys
-	EntryTime    time.Time    // Entry time
-	ExitTime     time.Time    // Exit time
-	PositionType PositionType // Position type (buy or sell)
-	MarketType   MarketType   // Market (Equities, ETFs)
-	Balance      float64      // Profit or loss
-	AssetID      uint         // Foreign key to Asset
-}
-
-func NewPosition(assetId uint, symb string, price float64, qty int, marketType MarketType) (*Position, error) {
+	Symbol       string          // Financial asset symbol
+	EntryPrice   float64         // Entry price
+	ExitPrice    float64         // Exit price
+	Quantity     decimal.Decimal `gorm:"type:numeric"` // Quantity of shares/buys, fractional for crypto and fractional shares
+	EntryTime    time.Time       // Entry time
+	ExitTime     time.Time       // Exit time
+	PositionType PositionType    // Position type (buy or sell)
+	MarketType   MarketType      // Market (Equities, ETFs, Options, Bonds, Crypto, FX)
+	Currency     string          // Currency of the prices, the cash account it moves
+	Balance      float64         // Profit or loss
+	AssetID      uint            // Foreign key to Asset
+
+	FractionalShares bool // The broker holds fractions of a share, kept by the splits instead of paid in cash
+
+	StopType         StopType  // Stop configuration (fixed, percent trailing, ATR trailing)
+	StopValue        float64   // Stop price, trailing percentage or ATR multiple
+	StopATR          float64   // ATR used by the ATR trailing stop
+	StopPrice        float64   // Current stop level
+	HighestPrice     float64   // Highest price since entry
+	StopTriggered    bool      // The price went through the stop
+	StopTriggeredAt  time.Time // Time of the breach
+	StopTriggerPrice float64   // Price of the breach
+
+	BorrowFeeRate   float64   // Annual fee of the borrowed shares of a short, in percentage
+	BorrowFees      float64   // Borrow fees accrued and not paid yet
+	BorrowAccruedAt time.Time // Last accrual of the borrow fees
+
+	Option OptionContract `gorm:"embedded;embeddedPrefix:option_"` // Terms of the contract when MarketType is Option
+}
+
+func NewPosition(assetId uint, symb string, price float64, qty decimal.Decimal, marketType MarketType) (*Position, error) {
 	if symb == "" {
 		return nil, errors.New("symbol cannot be empty")
 	}
@@ -77,6 +121,8 @@ const (
 	WeakTrend
 	ModerateTrend
 	None
+	Accumulation
+	Distribution
 )
 
 func (t TrendType) String() string {
@@ -107,12 +153,17 @@ func (t TrendType) String() string {
 		return "Lower Trading Risk"
 	case None:
 		return "None"
+	case Accumulation:
+		return "Accumulation"
+	case Distribution:
+		return "Distribution"
 	default:
 		return "Unknown TrendType"
 	}
 }
 
 type BasicMarketData struct {
+	Open      float64
 	High      float64
 	Low       float64
 	Close     float64
@@ -149,7 +200,8 @@ func RunAnalysis[T Analyzer](symbol string, marketDataList []BasicMarketData, in
 	}
 	err = analyzer.Analyze(marketDataList)
 	if err != nil {
-		return nil, fmt.Errorf("error analyzing trend: %w", err)
+		// The analyzer explains the failure in its result, keep it with the indexes
+		return analyzer.SetIndex(indexes), fmt.Errorf("error analyzing trend: %w", err)
 	}
 	ind := analyzer.SetIndex(indexes)
 	return ind, nil
@@ -169,6 +221,21 @@ type Indexes struct {
 	Momentum   Momentum
 	ADX        ADX
 	CCI        CCI
+
+	SupportResistance SupportResistance
+	Fibonacci         Fibonacci
+	Divergence        Divergence
+	Candlestick       Candlestick
+	Ichimoku          Ichimoku
+	VWAP              VWAP
+	MFI               MFI
+	WilliamsR         WilliamsR
+	CMF               CMF
+	Supertrend        Supertrend
+	Aroon             Aroon
+	TRIX              TRIX
+
+	MissingBars []time.Time // Bars the exchange calendar expected that the provider did not return
 }
 
 func NewIndexes(symbol string) *Indexes {
@@ -224,3 +291,51 @@ func NewCCIAdapter(symbol string) (Analyzer, error) {
 func NewADXAdapter(symbol string) (Analyzer, error) {
 	return NewADX(symbol)
 }
+
+func NewSupportResistanceAdapter(symbol string) (Analyzer, error) {
+	return NewSupportResistance(symbol)
+}
+
+func NewFibonacciAdapter(symbol string) (Analyzer, error) {
+	return NewFibonacci(symbol)
+}
+
+func NewDivergenceAdapter(symbol string) (Analyzer, error) {
+	return NewDivergence(symbol)
+}
+
+func NewCandlestickAdapter(symbol string) (Analyzer, error) {
+	return NewCandlestick(symbol)
+}
+
+func NewIchimokuAdapter(symbol string) (Analyzer, error) {
+	return NewIchimoku(symbol)
+}
+
+func NewVWAPAdapter(symbol string) (Analyzer, error) {
+	return NewVWAP(symbol)
+}
+
+func NewMFIAdapter(symbol string) (Analyzer, error) {
+	return NewMFI(symbol)
+}
+
+func NewWilliamsRAdapter(symbol string) (Analyzer, error) {
+	return NewWilliamsR(symbol)
+}
+
+func NewCMFAdapter(symbol string) (Analyzer, error) {
+	return NewCMF(symbol)
+}
+
+func NewSupertrendAdapter(symbol string) (Analyzer, error) {
+	return NewSupertrend(symbol)
+}
+
+func NewAroonAdapter(symbol string) (Analyzer, error) {
+	return NewAroon(symbol)
+}
+
+func NewTRIXAdapter(symbol string) (Analyzer, error) {
+	return NewTRIX(symbol)
+}
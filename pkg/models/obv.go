@@ -40,6 +40,9 @@ func (obv *OBV) calculateOBV(marketDataList []BasicMarketData) ([]float64, error
 	if len(closes) != len(volumes) {
 		return nil, fmt.Errorf("length of closes and volumes must be the same")
 	}
+	if len(closes) == 0 {
+		return nil, fmt.Errorf("not enough data to calculate OBV")
+	}
 
 	obvArray := make([]float64, len(closes))
 	obvArray[0] = float64(volumes[0])
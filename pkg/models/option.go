@@ -0,0 +1,188 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/megajandrox/go-finance-api/pkg/calendar"
+	"github.com/shopspring/decimal"
+)
+
+type OptionType int
+
+// Define constants representing the enumerator values
+const (
+	Call OptionType = iota
+	Put
+)
+
+func (t OptionType) String() string {
+	switch t {
+	case Call:
+		return "Call"
+	case Put:
+		return "Put"
+	default:
+		return "Unknown OptionType"
+	}
+}
+
+// ParseOptionType reads "call" or "put", in any case
+func ParseOptionType(value string) (OptionType, error) {
+	switch strings.ToLower(value) {
+	case "call":
+		return Call, nil
+	case "put":
+		return Put, nil
+	default:
+		return Call, errors.New("option type must be 'call' or 'put'")
+	}
+}
+
+// defaultOptionMultiplier is the shares delivered by a listed equity option contract
+const defaultOptionMultiplier = 100
+
+/*
+ * Terms of a listed option, persisted with the positions on it
+ */
+type OptionContract struct {
+	Underlying string     `json:"underlying"`  // Symbol of the underlying asset
+	Strike     float64    `json:"strike"`      // Strike price
+	Expiry     time.Time  `json:"expiry"`      // Expiration date
+	OptionType OptionType `json:"option_type"` // Call or put
+	Multiplier float64    `json:"multiplier"`  // Shares of the underlying per contract
+}
+
+// NewOptionContract validates the terms, the multiplier defaults to 100 shares
+func NewOptionContract(underlying string, strike float64, expiry time.Time, optionType OptionType, multiplier float64) (*OptionContract, error) {
+	if underlying == "" {
+		return nil, errors.New("underlying cannot be empty")
+	}
+	if strike <= 0 {
+		return nil, errors.New("strike must be positive")
+	}
+	if expiry.IsZero() {
+		return nil, errors.New("expiry cannot be empty")
+	}
+	if optionType != Call && optionType != Put {
+		return nil, errors.New("option type must be call or put")
+	}
+	if multiplier < 0 {
+		return nil, errors.New("multiplier cannot be negative")
+	}
+	if multiplier == 0 {
+		multiplier = defaultOptionMultiplier
+	}
+	return &OptionContract{Underlying: strings.ToUpper(underlying), Strike: strike, Expiry: expiry, OptionType: optionType, Multiplier: multiplier}, nil
+}
+
+// ExpiresAt returns the expiration time, the close of the underlying's session when the expiry is a date
+func (o OptionContract) ExpiresAt() time.Time {
+	exchange := calendar.ForSymbol(o.Underlying)
+	local := o.Expiry.In(exchange.Location())
+	if local.Hour() != 0 || local.Minute() != 0 || local.Second() != 0 {
+		return o.Expiry
+	}
+	if _, close, ok := exchange.Session(local); ok {
+		return close
+	}
+	return local.AddDate(0, 0, 1)
+}
+
+// YearsToExpiry returns the time left to the expiration in years of 365 days, zero once expired
+func (o OptionContract) YearsToExpiry(now time.Time) float64 {
+	return math.Max(o.ExpiresAt().Sub(now).Hours()/24/365, 0)
+}
+
+// IsOption reports whether the position is on an option contract
+func (p *Position) IsOption() bool {
+	return p.MarketType == Option
+}
+
+// SetOption makes the position a position on the contract
+func (p *Position) SetOption(contract OptionContract) {
+	p.MarketType = Option
+	p.Option = contract
+}
+
+// Multiplier converts the quantity to units of the price: the contract multiplier of an option, or the
+// face of a bond over the 100 its price is quoted on
+func (p *Position) Multiplier() float64 {
+	switch {
+	case p.IsOption() && p.Option.Multiplier > 0:
+		return p.Option.Multiplier
+	case p.MarketType == Bond:
+		return 1.0 / bondPriceBase
+	default:
+		return 1
+	}
+}
+
+// Notional returns the value of the quantity at the price
+func (p *Position) Notional(price float64, quantity decimal.Decimal) float64 {
+	return price * QuantityFloat(quantity) * p.Multiplier()
+}
+
+/*
+ * Pricing of an option contract from its underlying
+ */
+type OptionValuation struct {
+	Contract             OptionContract   `json:"contract"`
+	UnderlyingPrice      float64          `json:"underlying_price"`      // Latest price of the underlying
+	YearsToExpiry        float64          `json:"years_to_expiry"`       // Time left to the expiration
+	Rate                 float64          `json:"rate"`                  // Annual risk-free rate, decimal
+	HistoricalVolatility float64          `json:"historical_volatility"` // Annualized close-to-close volatility of the underlying
+	Theoretical          OptionGreeks     `json:"theoretical"`           // Value and greeks at the historical volatility
+	MarketPrice          float64          `json:"market_price,omitempty"`
+	ImpliedVolatility    float64          `json:"implied_volatility,omitempty"` // Volatility at which the value is the market price
+	Implied              *OptionGreeks    `json:"implied,omitempty"`            // Greeks at the implied volatility
+	ImpliedError         string           `json:"implied_error,omitempty"`      // Why the implied volatility could not be solved
+	Quantity             *decimal.Decimal `json:"quantity,omitempty"`           // Contracts of the position, negative when written
+	Position             *OptionGreeks    `json:"position,omitempty"`           // Greeks of the position
+}
+
+// ValueOption prices the contract at the historical volatility and, when there is a market price,
+// solves its implied volatility
+func ValueOption(contract OptionContract, spot float64, historicalVolatility float64, rate float64, marketPrice float64, now time.Time) (*OptionValuation, error) {
+	valuation := &OptionValuation{
+		Contract:             contract,
+		UnderlyingPrice:      spot,
+		YearsToExpiry:        contract.YearsToExpiry(now),
+		Rate:                 rate,
+		HistoricalVolatility: historicalVolatility,
+		MarketPrice:          marketPrice,
+	}
+	theoretical, err := BlackScholes(contract.OptionType, spot, contract.Strike, valuation.YearsToExpiry, rate, historicalVolatility)
+	if err != nil {
+		return nil, err
+	}
+	valuation.Theoretical = *theoretical
+	if marketPrice <= 0 {
+		return valuation, nil
+	}
+	impliedVolatility, err := ImpliedVolatility(contract.OptionType, marketPrice, spot, contract.Strike, valuation.YearsToExpiry, rate)
+	if err != nil {
+		valuation.ImpliedError = err.Error()
+		return valuation, nil
+	}
+	valuation.ImpliedVolatility = impliedVolatility
+	valuation.Implied, err = BlackScholes(contract.OptionType, spot, contract.Strike, valuation.YearsToExpiry, rate, impliedVolatility)
+	if err != nil {
+		return nil, err
+	}
+	return valuation, nil
+}
+
+// ForPosition scales the greeks by the contracts of the position, at the implied volatility when solved
+func (v *OptionValuation) ForPosition(p *Position) {
+	greeks := v.Theoretical
+	if v.Implied != nil {
+		greeks = *v.Implied
+	}
+	quantity := p.SignedQuantity()
+	v.Quantity = &quantity
+	scaled := greeks.Scale(QuantityFloat(quantity) * p.Multiplier())
+	v.Position = &scaled
+}
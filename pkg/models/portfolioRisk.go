@@ -0,0 +1,228 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/megajandrox/go-finance-api/pkg/calendar"
+	"github.com/shopspring/decimal"
+)
+
+/*
+ * Value at Risk and Conditional Value at Risk of the open positions, expressed as a loss in currency
+ */
+type PortfolioRisk struct {
+	Confidence          float64            `json:"confidence"`
+	Currency            string             `json:"currency,omitempty"` // Currency of the values
+	MarketValue         float64            `json:"market_value"`       // Net value, longs minus shorts
+	LongExposure        float64            `json:"long_exposure"`      // Value of the long holdings
+	ShortExposure       float64            `json:"short_exposure"`     // Value of the shares owed by the shorts
+	GrossExposure       float64            `json:"gross_exposure"`     // Longs plus shorts
+	Observations        int                `json:"observations"`       // Number of aligned daily returns
+	HistoricalVaR1Day   float64            `json:"historical_var_1d"`
+	HistoricalVaR10Day  float64            `json:"historical_var_10d"`
+	HistoricalCVaR1Day  float64            `json:"historical_cvar_1d"`
+	HistoricalCVaR10Day float64            `json:"historical_cvar_10d"`
+	ParametricVaR1Day   float64            `json:"parametric_var_1d"`
+	ParametricVaR10Day  float64            `json:"parametric_var_10d"`
+	ParametricCVaR1Day  float64            `json:"parametric_cvar_1d"`
+	ParametricCVaR10Day float64            `json:"parametric_cvar_10d"`
+	Contributions       []RiskContribution `json:"contributions"`
+}
+
+/*
+ * Share of the portfolio risk explained by one asset
+ */
+type RiskContribution struct {
+	Symbol              string  `json:"symbol"`
+	Quantity            float64 `json:"quantity"`             // Negative for a net short
+	MarketValue         float64 `json:"market_value"`         // Negative for a net short
+	Weight              float64 `json:"weight"`               // Market value over the gross exposure
+	MarginalVaR         float64 `json:"marginal_var"`         // Change of the parametric VaR per unit of currency added to the asset
+	ComponentVaR        float64 `json:"component_var"`        // Parametric 1-day VaR attributed to the asset, they add up to the total
+	ComponentCVaR       float64 `json:"component_cvar"`       // Historical 1-day CVaR attributed to the asset, they add up to the total
+	ContributionPercent float64 `json:"contribution_percent"` // Component VaR over the total parametric VaR
+}
+
+// OpenHoldings returns the net quantity of every symbol with open positions, negative when it is net short.
+// Option contracts count as their multiplier so the quantity values at the quoted price.
+func OpenHoldings(positions []Position) map[string]float64 {
+	net := map[string]decimal.Decimal{}
+	for _, p := range positions {
+		if p.IsOpen() {
+			net[p.Symbol] = net[p.Symbol].Add(p.SignedQuantity().Mul(decimal.NewFromFloat(p.Multiplier())))
+		}
+	}
+	holdings := map[string]float64{}
+	for symbol, quantity := range net {
+		if !quantity.IsZero() {
+			holdings[symbol] = QuantityFloat(quantity)
+		}
+	}
+	return holdings
+}
+
+// normalQuantile returns the inverse of the standard normal distribution
+func normalQuantile(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
+
+// normalDensity returns the standard normal probability density
+func normalDensity(x float64) float64 {
+	return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+}
+
+// alignedReturnMatrix returns the simple daily returns of every symbol on the trading days all of them have in common
+func alignedReturnMatrix(symbols []string, marketData map[string][]BasicMarketData) [][]float64 {
+	closes := make([]map[int64]float64, len(symbols))
+	counts := map[int64]int{}
+	for i, symbol := range symbols {
+		closes[i] = map[int64]float64{}
+		exchange := calendar.ForSymbol(symbol)
+		for _, d := range marketData[symbol] {
+			key := barKey(exchange, d.TimeStamp, true)
+			if _, seen := closes[i][key]; !seen {
+				counts[key]++
+			}
+			closes[i][key] = d.Close
+		}
+	}
+	var keys []int64
+	for key, count := range counts {
+		if count == len(symbols) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	returns := make([][]float64, len(symbols))
+	for k := 1; k < len(keys); k++ {
+		valid := true
+		for i := range symbols {
+			if closes[i][keys[k-1]] <= 0 || closes[i][keys[k]] <= 0 {
+				valid = false
+				break
+			}
+		}
+		if !valid {
+			continue
+		}
+		for i := range symbols {
+			returns[i] = append(returns[i], closes[i][keys[k]]/closes[i][keys[k-1]]-1)
+		}
+	}
+	return returns
+}
+
+// CalculatePortfolioRisk computes the VaR and CVaR of the holdings from the daily bars of every symbol,
+// short holdings have a negative quantity and lose when the price rises.
+// The 10-day figures scale the 1-day ones by the square root of time.
+func CalculatePortfolioRisk(holdings map[string]float64, marketData map[string][]BasicMarketData, confidence float64) (*PortfolioRisk, error) {
+	if confidence <= 0 || confidence >= 1 {
+		return nil, fmt.Errorf("confidence must be between 0 and 1, got %v", confidence)
+	}
+	if len(holdings) == 0 {
+		return nil, errors.New("there are no open positions")
+	}
+
+	symbols := make([]string, 0, len(holdings))
+	for symbol := range holdings {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	risk := &PortfolioRisk{Confidence: confidence}
+	values := make([]float64, len(symbols))
+	for i, symbol := range symbols {
+		bars := marketData[symbol]
+		if len(bars) == 0 {
+			return nil, fmt.Errorf("there are no bars for %s", symbol)
+		}
+		values[i] = holdings[symbol] * bars[len(bars)-1].Close
+		risk.MarketValue += values[i]
+		if values[i] > 0 {
+			risk.LongExposure += values[i]
+		} else {
+			risk.ShortExposure -= values[i]
+		}
+	}
+	risk.GrossExposure = risk.LongExposure + risk.ShortExposure
+	if risk.GrossExposure <= 0 {
+		return nil, errors.New("the market value of the open positions must be positive")
+	}
+
+	returns := alignedReturnMatrix(symbols, marketData)
+	observations := len(returns[0])
+	if observations < 20 {
+		return nil, fmt.Errorf("not enough aligned history to calculate the VaR, %d returns", observations)
+	}
+	risk.Observations = observations
+
+	// Historical simulation, profit or loss of today's holdings on every past day
+	pnl := make([]float64, observations)
+	for t := 0; t < observations; t++ {
+		for i := range symbols {
+			pnl[t] += values[i] * returns[i][t]
+		}
+	}
+	order := make([]int, observations)
+	for t := range order {
+		order[t] = t
+	}
+	sort.Slice(order, func(a, b int) bool { return pnl[order[a]] < pnl[order[b]] })
+	// The tolerance keeps 100 * (1 - 0.95), which is 5.000000000000004 in floating point, at 5 days
+	tail := int(math.Ceil(float64(observations)*(1-confidence) - 1e-9))
+	if tail < 1 {
+		tail = 1
+	}
+	risk.HistoricalVaR1Day = -pnl[order[tail-1]]
+	componentCVaR := make([]float64, len(symbols))
+	for _, t := range order[:tail] {
+		risk.HistoricalCVaR1Day -= pnl[t]
+		for i := range symbols {
+			componentCVaR[i] -= values[i] * returns[i][t]
+		}
+	}
+	risk.HistoricalCVaR1Day /= float64(tail)
+
+	// Variance-covariance method on the currency exposures, assuming zero mean returns
+	covarianceTimesValues := make([]float64, len(symbols))
+	for i := range symbols {
+		for j := range symbols {
+			covarianceTimesValues[i] += covariance(returns[i], returns[j]) * values[j]
+		}
+	}
+	variance := 0.0
+	for i := range symbols {
+		variance += values[i] * covarianceTimesValues[i]
+	}
+	deviation := math.Sqrt(variance)
+	z := normalQuantile(confidence)
+	risk.ParametricVaR1Day = z * deviation
+	risk.ParametricCVaR1Day = deviation * normalDensity(z) / (1 - confidence)
+
+	scale := math.Sqrt(10)
+	risk.HistoricalVaR10Day = risk.HistoricalVaR1Day * scale
+	risk.HistoricalCVaR10Day = risk.HistoricalCVaR1Day * scale
+	risk.ParametricVaR10Day = risk.ParametricVaR1Day * scale
+	risk.ParametricCVaR10Day = risk.ParametricCVaR1Day * scale
+
+	for i, symbol := range symbols {
+		contribution := RiskContribution{
+			Symbol:        symbol,
+			Quantity:      holdings[symbol],
+			MarketValue:   values[i],
+			Weight:        values[i] / risk.GrossExposure,
+			ComponentCVaR: componentCVaR[i] / float64(tail),
+		}
+		if deviation > 0 {
+			contribution.MarginalVaR = z * covarianceTimesValues[i] / deviation
+			contribution.ComponentVaR = values[i] * contribution.MarginalVaR
+			contribution.ContributionPercent = contribution.ComponentVaR / risk.ParametricVaR1Day * 100
+		}
+		risk.Contributions = append(risk.Contributions, contribution)
+	}
+	return risk, nil
+}
@@ -0,0 +1,173 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// barsFromReturns builds daily bars that start at 100 and move by each simple return
+func barsFromReturns(returns []float64) []BasicMarketData {
+	bars := make([]BasicMarketData, len(returns)+1)
+	close := 100.0
+	for i := range bars {
+		if i > 0 {
+			close *= 1 + returns[i-1]
+		}
+		bars[i] = BasicMarketData{Open: close, High: close, Low: close, Close: close, TimeStamp: int64(1704115800 + i*86400)}
+	}
+	return bars
+}
+
+// gridReturns returns -5%, -4.9%, ... 4.9%, shuffled so the order of the days does not matter
+func gridReturns() []float64 {
+	returns := make([]float64, 100)
+	for k := range returns {
+		returns[(k*37)%100] = float64(k-50) / 1000
+	}
+	return returns
+}
+
+func TestNormalQuantile(t *testing.T) {
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{0.5, 0},
+		{0.95, 1.6448536270},
+		{0.975, 1.9599639845},
+		{0.99, 2.3263478740},
+	}
+	for _, tt := range tests {
+		if got := normalQuantile(tt.p); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("normalQuantile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestCalculatePortfolioRiskSingleAsset(t *testing.T) {
+	bars := barsFromReturns(gridReturns())
+	last := bars[len(bars)-1].Close
+	// Sample deviation of the grid: 0.001 * sqrt(100 * 101 / 12)
+	deviation := 0.001 * math.Sqrt(100*101/12.0)
+	tests := []struct {
+		name                string
+		quantity            float64
+		wantVaR, wantCVaR   float64 // Historical, per unit of market value
+		wantLong, wantShort float64
+	}{
+		// The 5 worst days of a long are -5% to -4.6%
+		{"long", 10, 0.046, 0.048, 10 * last, 0},
+		// A short loses on the 5 best days, 4.9% to 4.5%
+		{"short", -10, 0.045, 0.047, 0, 10 * last},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			risk, err := CalculatePortfolioRisk(map[string]float64{"AAPL": tt.quantity}, map[string][]BasicMarketData{"AAPL": bars}, 0.95)
+			if err != nil {
+				t.Fatal(err)
+			}
+			value := math.Abs(tt.quantity) * last
+			checks := []struct {
+				name      string
+				got, want float64
+			}{
+				{"observations", float64(risk.Observations), 100},
+				{"long exposure", risk.LongExposure, tt.wantLong},
+				{"short exposure", risk.ShortExposure, tt.wantShort},
+				{"historical VaR", risk.HistoricalVaR1Day, tt.wantVaR * value},
+				{"historical CVaR", risk.HistoricalCVaR1Day, tt.wantCVaR * value},
+				{"parametric VaR", risk.ParametricVaR1Day, 1.6448536270 * deviation * value},
+				// The density at the 95% quantile is 0.1031356, over the 5% tail
+				{"parametric CVaR", risk.ParametricCVaR1Day, 0.1031356404 / 0.05 * deviation * value},
+				{"10-day VaR", risk.HistoricalVaR10Day, tt.wantVaR * value * math.Sqrt(10)},
+			}
+			for _, c := range checks {
+				if math.Abs(c.got-c.want) > 1e-6*math.Max(1, math.Abs(c.want)) {
+					t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCalculatePortfolioRiskContributionsAddUp(t *testing.T) {
+	grid := gridReturns()
+	other := make([]float64, len(grid))
+	for i, r := range grid {
+		other[i] = 0.5*r + 0.01*math.Sin(float64(i))
+	}
+	marketData := map[string][]BasicMarketData{"AAPL": barsFromReturns(grid), "MSFT": barsFromReturns(other)}
+	risk, err := CalculatePortfolioRisk(map[string]float64{"AAPL": 10, "MSFT": -4}, marketData, 0.99)
+	if err != nil {
+		t.Fatal(err)
+	}
+	componentVaR, componentCVaR := 0.0, 0.0
+	for _, c := range risk.Contributions {
+		componentVaR += c.ComponentVaR
+		componentCVaR += c.ComponentCVaR
+	}
+	if math.Abs(componentVaR-risk.ParametricVaR1Day) > 1e-9 {
+		t.Errorf("component VaRs add up to %v, the VaR is %v", componentVaR, risk.ParametricVaR1Day)
+	}
+	if math.Abs(componentCVaR-risk.HistoricalCVaR1Day) > 1e-9 {
+		t.Errorf("component CVaRs add up to %v, the CVaR is %v", componentCVaR, risk.HistoricalCVaR1Day)
+	}
+	if risk.GrossExposure != risk.LongExposure+risk.ShortExposure {
+		t.Errorf("gross exposure %v is not longs %v plus shorts %v", risk.GrossExposure, risk.LongExposure, risk.ShortExposure)
+	}
+}
+
+func TestCalculatePortfolioRiskErrors(t *testing.T) {
+	bars := barsFromReturns(gridReturns())
+	tests := []struct {
+		name       string
+		holdings   map[string]float64
+		marketData map[string][]BasicMarketData
+		confidence float64
+	}{
+		{"confidence of 1", map[string]float64{"AAPL": 1}, map[string][]BasicMarketData{"AAPL": bars}, 1},
+		{"no holdings", map[string]float64{}, map[string][]BasicMarketData{"AAPL": bars}, 0.95},
+		{"no bars", map[string]float64{"AAPL": 1}, map[string][]BasicMarketData{}, 0.95},
+		{"short history", map[string]float64{"AAPL": 1}, map[string][]BasicMarketData{"AAPL": bars[:20]}, 0.95},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CalculatePortfolioRisk(tt.holdings, tt.marketData, tt.confidence); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestOpenHoldings(t *testing.T) {
+	quantity := func(value string) decimal.Decimal {
+		q, err := decimal.NewFromString(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return q
+	}
+	positions := []Position{
+		{Symbol: "AAPL", PositionType: Bought, Quantity: quantity("10")},
+		{Symbol: "AAPL", PositionType: Shorted, Quantity: quantity("4")},
+		{Symbol: "AAPL", PositionType: Sold, Quantity: quantity("0")},
+		{Symbol: "BTC-USD", PositionType: Bought, Quantity: quantity("0.1")},
+		{Symbol: "BTC-USD", PositionType: Bought, Quantity: quantity("0.2")},
+		{Symbol: "AAPL240621C00200000", PositionType: Bought, MarketType: Option, Option: OptionContract{Multiplier: 100}, Quantity: quantity("2")},
+		{Symbol: "MSFT", PositionType: Shorted, Quantity: quantity("5")},
+		{Symbol: "MSFT", PositionType: Bought, Quantity: quantity("5")},
+	}
+	want := map[string]float64{"AAPL": 6, "BTC-USD": 0.3, "AAPL240621C00200000": 200}
+	got := OpenHoldings(positions)
+	if len(got) != len(want) {
+		t.Fatalf("OpenHoldings = %v, want %v", got, want)
+	}
+	for symbol, q := range want {
+		// 0.1 + 0.2 is exact in decimal
+		if got[symbol] != q {
+			t.Errorf("%s = %v, want %v", symbol, got[symbol], q)
+		}
+	}
+}
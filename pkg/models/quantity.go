@@ -0,0 +1,49 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// quantityPlaces is the precision of the quantities, enough for the satoshis of a bitcoin
+const quantityPlaces = 8
+
+func init() {
+	// Quantities are exact decimals but they stay JSON numbers, like every other amount of the API
+	decimal.MarshalJSONWithoutQuotes = true
+}
+
+// QuantityFloat returns the quantity as a float64 for the price, P&L and risk calculations
+func QuantityFloat(quantity decimal.Decimal) float64 {
+	f, _ := quantity.Float64()
+	return f
+}
+
+// AllowsFractions reports whether the position can hold a fraction of a unit: crypto and FX pairs, and the
+// positions opened with fractional shares. Options are whole contracts.
+func (p *Position) AllowsFractions() bool {
+	if p.IsOption() {
+		return false
+	}
+	return p.IsCurrencyPair() || p.FractionalShares
+}
+
+// ValidateQuantity checks a quantity to trade on the position: positive, at most 8 decimal places and
+// whole units unless the position allows fractions
+func (p *Position) ValidateQuantity(quantity decimal.Decimal) error {
+	if !quantity.IsPositive() {
+		return errors.New("quantity must be positive")
+	}
+	if !quantity.Equal(quantity.Round(quantityPlaces)) {
+		return fmt.Errorf("quantity cannot have more than %d decimal places", quantityPlaces)
+	}
+	if !p.AllowsFractions() && !quantity.Equal(quantity.Floor()) {
+		if p.IsOption() {
+			return errors.New("options are traded in whole contracts")
+		}
+		return errors.New("fractions of a share need fractional shares enabled on the position")
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/megajandrox/go-finance-api/pkg/calendar"
+)
+
+// intervalDurations are the fixed length intervals, the longer ones follow the calendar
+var intervalDurations = map[string]time.Duration{
+	"1m":  time.Minute,
+	"2m":  2 * time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"30m": 30 * time.Minute,
+	"60m": time.Hour,
+	"90m": 90 * time.Minute,
+	"1h":  time.Hour,
+}
+
+// maxLookback is how far back the provider serves bars of each interval, the missing ones have no limit
+var maxLookback = map[string]time.Duration{
+	"1m":  7 * 24 * time.Hour,
+	"2m":  60 * 24 * time.Hour,
+	"5m":  60 * 24 * time.Hour,
+	"15m": 60 * 24 * time.Hour,
+	"30m": 60 * 24 * time.Hour,
+	"60m": 730 * 24 * time.Hour,
+	"90m": 60 * 24 * time.Hour,
+	"1h":  730 * 24 * time.Hour,
+}
+
+// MaxLookback returns how far back the bars of the interval are available, zero when there is no limit
+func MaxLookback(interval string) time.Duration {
+	return maxLookback[interval]
+}
+
+// IntervalDuration returns the length of the bars of an intraday interval
+func IntervalDuration(interval string) (time.Duration, bool) {
+	duration, ok := intervalDurations[interval]
+	return duration, ok
+}
+
+// IsIntradayInterval reports whether the interval splits the trading day
+func IsIntradayInterval(interval string) bool {
+	_, ok := intervalDurations[interval]
+	return ok
+}
+
+// Resample aggregates finer bars into bars of the interval: the open of the first bar, the highest high,
+// the lowest low, the close of the last bar and the summed volume. Buckets follow the trading days of the
+// exchange, the intraday ones are counted from the session open, the weekly ones start on Monday and the
+// monthly ones on day 1. The resampled bar keeps the timestamp of its first bar.
+func Resample(data []BasicMarketData, interval string, exchange *calendar.Exchange) ([]BasicMarketData, error) {
+	var bucketOf func(timestamp int64) int64
+	if duration, ok := intervalDurations[interval]; ok {
+		seconds := int64(duration / time.Second)
+		bucketOf = func(timestamp int64) int64 {
+			open := exchange.SessionOpen(timestamp)
+			offset := timestamp - open
+			if offset < 0 {
+				// Pre-market bars fall in buckets before the open
+				offset -= seconds - 1
+			}
+			return open + offset/seconds*seconds
+		}
+	} else {
+		switch interval {
+		case "1d":
+			bucketOf = func(timestamp int64) int64 {
+				return exchange.TradingDay(timestamp).Unix()
+			}
+		case "1wk":
+			bucketOf = func(timestamp int64) int64 {
+				date := exchange.TradingDay(timestamp)
+				return date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7)).Unix()
+			}
+		case "1mo":
+			bucketOf = func(timestamp int64) int64 {
+				date := exchange.TradingDay(timestamp)
+				return date.AddDate(0, 0, 1-date.Day()).Unix()
+			}
+		default:
+			return nil, fmt.Errorf("unsupported interval %q", interval)
+		}
+	}
+
+	var resampled []BasicMarketData
+	var bucket int64
+	for i, d := range data {
+		currentBucket := bucketOf(d.TimeStamp)
+		if i == 0 || currentBucket != bucket {
+			bucket = currentBucket
+			resampled = append(resampled, d)
+			continue
+		}
+		last := &resampled[len(resampled)-1]
+		if d.High > last.High {
+			last.High = d.High
+		}
+		if d.Low < last.Low {
+			last.Low = d.Low
+		}
+		last.Close = d.Close
+		last.Volume += d.Volume
+	}
+	return resampled, nil
+}
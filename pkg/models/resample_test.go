@@ -0,0 +1,138 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/megajandrox/go-finance-api/pkg/calendar"
+)
+
+// bar builds a bar that opens at the RFC 3339 time
+func bar(t *testing.T, at string, open, high, low, close float64, volume int64) BasicMarketData {
+	t.Helper()
+	ts, err := time.Parse(time.RFC3339, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return BasicMarketData{Open: open, High: high, Low: low, Close: close, Volume: volume, TimeStamp: ts.Unix()}
+}
+
+func TestResample(t *testing.T) {
+	nyse, err := calendar.Get("NYSE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	byma, err := calendar.Get("BYMA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		interval string
+		exchange *calendar.Exchange
+		data     []BasicMarketData
+		want     []BasicMarketData
+	}{
+		{
+			name:     "5m into 15m from the open",
+			interval: "15m",
+			exchange: nyse,
+			data: []BasicMarketData{
+				bar(t, "2024-07-01T13:30:00Z", 10, 11, 9, 10.5, 100),
+				bar(t, "2024-07-01T13:35:00Z", 10.5, 12, 10, 11, 200),
+				bar(t, "2024-07-01T13:40:00Z", 11, 11.5, 8, 9, 300),
+				bar(t, "2024-07-01T13:45:00Z", 9, 9.5, 8.5, 9.2, 400),
+			},
+			want: []BasicMarketData{
+				bar(t, "2024-07-01T13:30:00Z", 10, 12, 8, 9, 600),
+				bar(t, "2024-07-01T13:45:00Z", 9, 9.5, 8.5, 9.2, 400),
+			},
+		},
+		{
+			// 13:20 and 13:25 are in the 15 minutes before the open, 13:10 in the ones before
+			name:     "pre-market buckets before the open",
+			interval: "15m",
+			exchange: nyse,
+			data: []BasicMarketData{
+				bar(t, "2024-07-01T13:10:00Z", 10, 10, 10, 10, 1),
+				bar(t, "2024-07-01T13:20:00Z", 11, 11, 11, 11, 1),
+				bar(t, "2024-07-01T13:25:00Z", 12, 12, 12, 12, 1),
+			},
+			want: []BasicMarketData{
+				bar(t, "2024-07-01T13:10:00Z", 10, 10, 10, 10, 1),
+				bar(t, "2024-07-01T13:20:00Z", 11, 12, 11, 12, 2),
+			},
+		},
+		{
+			// BYMA opens at 11:00 in Buenos Aires, 14:00 UTC
+			name:     "hours from the session open of the exchange",
+			interval: "1h",
+			exchange: byma,
+			data: []BasicMarketData{
+				bar(t, "2024-07-01T14:00:00Z", 10, 11, 9, 10, 1),
+				bar(t, "2024-07-01T14:30:00Z", 10, 13, 10, 12, 1),
+				bar(t, "2024-07-01T15:00:00Z", 12, 12, 11, 11, 1),
+			},
+			want: []BasicMarketData{
+				bar(t, "2024-07-01T14:00:00Z", 10, 13, 9, 12, 2),
+				bar(t, "2024-07-01T15:00:00Z", 12, 12, 11, 11, 1),
+			},
+		},
+		{
+			name:     "days into weeks starting on Monday",
+			interval: "1wk",
+			exchange: nyse,
+			data: []BasicMarketData{
+				bar(t, "2024-07-01T13:30:00Z", 10, 11, 9, 10, 1),
+				bar(t, "2024-07-03T13:30:00Z", 10, 14, 10, 13, 1),
+				bar(t, "2024-07-05T13:30:00Z", 13, 13, 7, 8, 1),
+				bar(t, "2024-07-08T13:30:00Z", 8, 9, 8, 9, 1),
+			},
+			want: []BasicMarketData{
+				bar(t, "2024-07-01T13:30:00Z", 10, 14, 7, 8, 3),
+				bar(t, "2024-07-08T13:30:00Z", 8, 9, 8, 9, 1),
+			},
+		},
+		{
+			// 23:00 in Buenos Aires on June 30 is already July 1 in UTC
+			name:     "months in the time zone of the exchange",
+			interval: "1mo",
+			exchange: byma,
+			data: []BasicMarketData{
+				bar(t, "2024-06-28T14:00:00Z", 10, 11, 9, 10, 1),
+				bar(t, "2024-07-01T02:00:00Z", 10, 12, 10, 11, 1),
+				bar(t, "2024-07-01T14:00:00Z", 11, 11, 5, 6, 1),
+			},
+			want: []BasicMarketData{
+				bar(t, "2024-06-28T14:00:00Z", 10, 12, 9, 11, 2),
+				bar(t, "2024-07-01T14:00:00Z", 11, 11, 5, 6, 1),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resample(tt.data, tt.interval, tt.exchange)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d bars, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("bar %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestResampleUnsupportedInterval(t *testing.T) {
+	nyse, err := calendar.Get("NYSE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Resample(nil, "3d", nyse); err == nil {
+		t.Error("expected an error for 3d")
+	}
+}
@@ -3,6 +3,8 @@ package models
 import (
 	"errors"
 	"fmt"
+
+	"github.com/megajandrox/go-finance-api/pkg/calendar"
 )
 
 type RSI struct {
@@ -26,7 +28,7 @@ func (i *RSI) SetIndex(indexes *Indexes) *Indexes {
 
 func (rsi *RSI) calculate(marketDataList []BasicMarketData) (bool, error) {
 	// Calculate RSI for 14-day period
-	dailyCloses := ExtractDailyCloses(marketDataList)
+	dailyCloses := ExtractDailyCloses(marketDataList, calendar.ForSymbol(rsi.symbol))
 	rsiArray, err := rsi.calculateRSI(dailyCloses, 14)
 	if err != nil {
 		return false, fmt.Errorf(`Error calculating RSI: %v`, err)
@@ -38,7 +40,8 @@ func (rsi *RSI) calculate(marketDataList []BasicMarketData) (bool, error) {
 
 // CalculateRSI calculates the RSI for a given period
 func (rsi *RSI) calculateRSI(marketDataList []BasicMarketData, period int) ([]float64, error) {
-	if len(marketDataList) < period {
+	// The first average needs period changes, one bar more than the period
+	if len(marketDataList) <= period {
 		return nil, fmt.Errorf("not enough data to calculate RSI for the given period")
 	}
 
@@ -71,17 +74,17 @@ func (rsi *RSI) calculateRSI(marketDataList []BasicMarketData, period int) ([]fl
 
 	// Calculate RSI for the first period
 	rs := avgGain / avgLoss
-	rsiArray[period-1] = 100 - (100 / (1 + rs))
+	rsiArray[period] = 100 - (100 / (1 + rs))
 
 	// Calculate RSI for subsequent periods
-	for i := period; i < len(marketDataList); i++ {
+	for i := period + 1; i < len(marketDataList); i++ {
 		avgGain = (avgGain*float64(period-1) + gains[i]) / float64(period)
 		avgLoss = (avgLoss*float64(period-1) + losses[i]) / float64(period)
 		rs = avgGain / avgLoss
 		rsiArray[i] = 100 - (100 / (1 + rs))
 	}
 
-	return rsiArray[period-1:], nil // Return only the valid RSI values
+	return rsiArray[period:], nil // Return only the valid RSI values
 }
 
 // analyzeRSI analyzes the RSI value and returns a descriptive analysis
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+// barsFromCloses builds daily bars whose high and low are one unit around the close
+func barsFromCloses(closes ...float64) []BasicMarketData {
+	bars := make([]BasicMarketData, len(closes))
+	for i, c := range closes {
+		bars[i] = BasicMarketData{Open: c, High: c + 1, Low: c - 1, Close: c, Volume: 1000, TimeStamp: int64(1704067200 + i*86400)}
+	}
+	return bars
+}
+
+func TestCalculateRSI(t *testing.T) {
+	rising := make([]float64, 15)
+	alternating := make([]float64, 15)
+	for i := range rising {
+		rising[i] = float64(100 + i)
+		alternating[i] = 100
+		if i%2 == 1 {
+			alternating[i] = 101
+		}
+	}
+	tests := []struct {
+		name    string
+		closes  []float64
+		want    float64
+		wantErr bool
+	}{
+		{"exactly the period", rising[:14], 0, true},
+		{"only gains", rising, 100, false},
+		// Seven rises and seven falls of the same size
+		{"balanced changes", alternating, 50, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := (&RSI{}).calculateRSI(barsFromCloses(tt.closes...), 14)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", values)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := values[len(values)-1]; math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("RSI = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRSIExpressionNeedsMoreBarsThanThePeriod(t *testing.T) {
+	expression, err := ParseExpression("rsi(14) > 50")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closes := make([]float64, 14)
+	for i := range closes {
+		closes[i] = float64(100 + i)
+	}
+	if _, err := expression.Evaluate(barsFromCloses(closes...)); err == nil {
+		t.Error("expected an error for 14 bars")
+	}
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// borrowDayCount is the day count convention of the stock borrow fees
+const borrowDayCount = 360
+
+func NewShortPosition(assetId uint, symb string, price float64, qty decimal.Decimal, marketType MarketType, borrowFeeRate float64) (*Position, error) {
+	if borrowFeeRate < 0 {
+		return nil, errors.New("borrow fee rate cannot be negative")
+	}
+	position, err := NewPosition(assetId, symb, price, qty, marketType)
+	if err != nil {
+		return nil, err
+	}
+	position.PositionType = Shorted
+	position.BorrowFeeRate = borrowFeeRate
+	position.BorrowAccruedAt = position.EntryTime
+	return position, nil
+}
+
+// IsShort reports whether the position was opened by a short sale
+func (p *Position) IsShort() bool {
+	return p.PositionType == Shorted || p.PositionType == Covered
+}
+
+// IsOpen reports whether the position still holds or owes shares
+func (p *Position) IsOpen() bool {
+	return (p.PositionType == Bought || p.PositionType == Shorted) && p.Quantity.IsPositive()
+}
+
+// SignedQuantity returns the quantity held, negative for the shares owed by a short
+func (p *Position) SignedQuantity() decimal.Decimal {
+	if p.IsShort() {
+		return p.Quantity.Neg()
+	}
+	return p.Quantity
+}
+
+// PnL returns the price profit or loss of closing the quantity at the price, a short gains when the price falls
+func (p *Position) PnL(price float64, quantity decimal.Decimal) float64 {
+	if p.IsShort() {
+		return p.Notional(p.EntryPrice-price, quantity)
+	}
+	return p.Notional(price-p.EntryPrice, quantity)
+}
+
+// AccrueBorrowFee adds the fee of the borrowed shares, valued at the price, since the last accrual
+func (p *Position) AccrueBorrowFee(price float64, now time.Time) float64 {
+	if p.PositionType != Shorted || !p.Quantity.IsPositive() || p.BorrowFeeRate == 0 || !now.After(p.BorrowAccruedAt) {
+		return 0
+	}
+	days := now.Sub(p.BorrowAccruedAt).Hours() / 24
+	fee := price * QuantityFloat(p.Quantity) * p.BorrowFeeRate / 100 * days / borrowDayCount
+	p.BorrowFees += fee
+	p.BorrowAccruedAt = now
+	return fee
+}
+
+// Cover buys back the quantity of the short at the price, the accrued borrow fees of the covered shares
+// are charged to its balance. It returns those fees.
+func (p *Position) Cover(price float64, quantity decimal.Decimal, now time.Time) (float64, error) {
+	if p.PositionType != Shorted {
+		return 0, errors.New("only open short positions can be covered")
+	}
+	if err := p.ValidateQuantity(quantity); err != nil {
+		return 0, err
+	}
+	if quantity.GreaterThan(p.Quantity) {
+		return 0, errors.New("quantity cannot be greater than the shares owed")
+	}
+	p.AccrueBorrowFee(price, now)
+	fees := p.BorrowFees * QuantityFloat(quantity.Div(p.Quantity))
+	p.BorrowFees -= fees
+	p.Balance += p.PnL(price, quantity) - fees
+	p.Quantity = p.Quantity.Sub(quantity)
+	p.ExitPrice = price
+	p.ExitTime = now
+	if p.Quantity.IsZero() {
+		p.PositionType = Covered
+	}
+	return fees, nil
+}
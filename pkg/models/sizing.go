@@ -0,0 +1,108 @@
+package models
+
+import (
+	"errors"
+	"math"
+
+	"github.com/shopspring/decimal"
+)
+
+/*
+ * Result of sizing a long position from the account risk
+ */
+type PositionSize struct {
+	instrument            *Position       // Market type and contract terms of the position sized
+	EntryPrice            float64         `json:"entry_price"`             // Planned entry price
+	StopPrice             float64         `json:"stop_price"`              // Planned stop price
+	RiskPerShare          float64         `json:"risk_per_share"`          // Entry minus stop
+	Multiplier            float64         `json:"multiplier"`              // Units of the price per unit of quantity, the shares of an option contract
+	RiskPerUnit           float64         `json:"risk_per_unit"`           // Risk per share times the multiplier, the risk of a contract
+	RiskAmount            float64         `json:"risk_amount"`             // Equity at risk allowed for the trade
+	Quantity              decimal.Decimal `json:"quantity"`                // Quantity to buy, whole units unless the instrument allows fractions
+	Notional              float64         `json:"notional"`                // Cost of the quantity at the entry price
+	MaxLoss               float64         `json:"max_loss"`                // Loss if the stop is hit
+	CurrentExposure       float64         `json:"current_exposure"`        // Cost of the open positions in the symbol
+	CappedByConcentration bool            `json:"capped_by_concentration"` // The concentration limit reduced the quantity
+}
+
+// CalculatePositionSize sizes a position on the instrument, its market type and option contract, so that
+// hitting the stop loses riskPercent of the equity. Amounts are computed like Position.Notional, with the
+// multiplier of the contract. The quantity is rounded down to the precision of the quantities when the
+// instrument allows fractions, to whole shares or contracts otherwise.
+func CalculatePositionSize(equity float64, riskPercent float64, entryPrice float64, stopPrice float64, instrument *Position) (*PositionSize, error) {
+	if equity <= 0 {
+		return nil, errors.New("equity must be positive")
+	}
+	if riskPercent <= 0 || riskPercent > 100 {
+		return nil, errors.New("risk percentage must be between 0 and 100")
+	}
+	if entryPrice <= 0 {
+		return nil, errors.New("entry price must be positive")
+	}
+	if stopPrice <= 0 || stopPrice >= entryPrice {
+		return nil, errors.New("stop price must be positive and below the entry price")
+	}
+	size := &PositionSize{
+		instrument:   instrument,
+		EntryPrice:   entryPrice,
+		StopPrice:    stopPrice,
+		RiskPerShare: entryPrice - stopPrice,
+		Multiplier:   instrument.Multiplier(),
+		RiskAmount:   equity * riskPercent / 100,
+	}
+	size.RiskPerUnit = size.RiskPerShare * size.Multiplier
+	size.setQuantity(size.quantityFor(size.RiskAmount, size.RiskPerUnit))
+	return size, nil
+}
+
+// ATRStopPrice returns the stop multiple ATRs below the entry price
+func ATRStopPrice(entryPrice float64, atr float64, multiple float64) (float64, error) {
+	if atr <= 0 {
+		return 0, errors.New("ATR must be positive")
+	}
+	if multiple <= 0 {
+		return 0, errors.New("ATR multiple must be positive")
+	}
+	return entryPrice - multiple*atr, nil
+}
+
+// CapByConcentration limits the position so the symbol does not exceed limitPercent of the equity
+func (s *PositionSize) CapByConcentration(equity float64, limitPercent float64, currentExposure float64) error {
+	if limitPercent <= 0 || limitPercent > 100 {
+		return errors.New("concentration limit must be between 0 and 100")
+	}
+	s.CurrentExposure = currentExposure
+	available := math.Max(equity*limitPercent/100-currentExposure, 0)
+	maxQuantity := s.quantityFor(available, s.EntryPrice*s.Multiplier)
+	if maxQuantity.LessThan(s.Quantity) {
+		s.setQuantity(maxQuantity)
+		s.CappedByConcentration = true
+	}
+	return nil
+}
+
+// quantityFor returns the quantity the amount pays at the price per unit, rounded down
+func (s *PositionSize) quantityFor(amount float64, price float64) decimal.Decimal {
+	quantity := decimal.NewFromFloat(amount).DivRound(decimal.NewFromFloat(price), quantityPlaces+2).Truncate(quantityPlaces)
+	if !s.instrument.AllowsFractions() {
+		return quantity.Floor()
+	}
+	return quantity
+}
+
+func (s *PositionSize) setQuantity(quantity decimal.Decimal) {
+	s.Quantity = quantity
+	s.Notional = s.instrument.Notional(s.EntryPrice, quantity)
+	s.MaxLoss = s.instrument.Notional(s.RiskPerShare, quantity)
+}
+
+// OpenExposure returns the cost of the open long positions in the symbol
+func OpenExposure(positions []Position, symbol string) float64 {
+	exposure := 0.0
+	for _, p := range positions {
+		if p.Symbol == symbol && p.PositionType == Bought && p.Quantity.IsPositive() {
+			exposure += p.Notional(p.EntryPrice, p.Quantity)
+		}
+	}
+	return exposure
+}
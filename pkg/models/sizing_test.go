@@ -0,0 +1,143 @@
+package models
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// optionInstrument returns an option position on a contract of multiplier shares, 100 when zero
+func optionInstrument(t *testing.T, multiplier float64) *Position {
+	t.Helper()
+	contract, err := NewOptionContract("AAPL", 200, day(2030, time.January, 18), Call, multiplier)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	position := &Position{Symbol: "AAPL300118C00200000"}
+	position.SetOption(*contract)
+	return position
+}
+
+func TestCalculatePositionSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		equity        float64
+		riskPercent   float64
+		entry         float64
+		stop          float64
+		instrument    *Position
+		concentration float64
+		exposure      float64
+		want          string
+		wantCapped    bool
+	}{
+		// 1% of 10000 at 2 per share
+		{"whole shares", 10000, 1, 50, 48, &Position{MarketType: Equity}, 0, 0, "50", false},
+		// 100 / 3 keeps 8 decimal places, rounded down
+		{"fractional shares", 10000, 1, 50, 47, &Position{MarketType: Equity, FractionalShares: true}, 0, 0, "33.33333333", false},
+		{"whole shares rounded down", 10000, 1, 50, 47, &Position{MarketType: Equity}, 0, 0, "33", false},
+		{"crypto", 5000, 2, 60000, 57000, &Position{MarketType: Crypto}, 0, 0, "0.03333333", false},
+		// A contract of 100 shares risks 300, more than the 100 allowed
+		{"option contract above the risk", 10000, 1, 5, 2, optionInstrument(t, 0), 0, 0, "0", false},
+		// 1000 / 300 rounded down to whole contracts
+		{"options in whole contracts", 100000, 1, 5, 2, optionInstrument(t, 0), 0, 0, "3", false},
+		// 1000 / 30 with a contract of 10 shares
+		{"mini options", 100000, 1, 5, 2, optionInstrument(t, 10), 0, 0, "33", false},
+		// 10% of 100000 is 10000, 8500 already held leaves 1500, three contracts at 500
+		{"options capped by concentration", 100000, 2, 5, 2, optionInstrument(t, 0), 10, 8500, "3", true},
+		// 10% of 10000 is 1000, 400 already held leaves 600 at 50
+		{"capped by concentration", 10000, 5, 50, 48, &Position{MarketType: Equity}, 10, 400, "12", true},
+		{"concentration above the risk", 10000, 1, 50, 48, &Position{MarketType: Equity}, 50, 0, "50", false},
+		{"concentration already used", 10000, 1, 50, 48, &Position{MarketType: Equity}, 10, 1500, "0", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size, err := CalculatePositionSize(tt.equity, tt.riskPercent, tt.entry, tt.stop, tt.instrument)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.concentration > 0 {
+				if err := size.CapByConcentration(tt.equity, tt.concentration, tt.exposure); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			want, _ := decimal.NewFromString(tt.want)
+			if !size.Quantity.Equal(want) {
+				t.Errorf("quantity = %s, want %s", size.Quantity, tt.want)
+			}
+			if size.CappedByConcentration != tt.wantCapped {
+				t.Errorf("capped = %v, want %v", size.CappedByConcentration, tt.wantCapped)
+			}
+			units := QuantityFloat(want) * tt.instrument.Multiplier()
+			if math.Abs(size.Notional-units*tt.entry) > 1e-9 || math.Abs(size.MaxLoss-units*(tt.entry-tt.stop)) > 1e-9 {
+				t.Errorf("notional and max loss = %v %v, want %v %v", size.Notional, size.MaxLoss, units*tt.entry, units*(tt.entry-tt.stop))
+			}
+			if size.MaxLoss > size.RiskAmount+1e-9 {
+				t.Errorf("max loss %v above the risk amount %v", size.MaxLoss, size.RiskAmount)
+			}
+		})
+	}
+}
+
+func TestCalculatePositionSizeFromATR(t *testing.T) {
+	tests := []struct {
+		name     string
+		bars     []BasicMarketData
+		multiple float64
+		want     string
+		wantErr  bool
+	}{
+		// ATR of 2, the stop 2 ATRs below 50 risks 4 per share
+		{"constant range", barsWithRanges(2, 2, 2, 2, 2), 2, "25", false},
+		// Wilder smoothing (4*2 + 8) / 3, the stop 1.5 ATRs below 50 risks 8 per share, 12.5 rounded down
+		{"smoothed range", barsWithRanges(2, 2, 4, 6, 8), 1.5, "12", false},
+		{"no range", barsWithRanges(0, 0, 0, 0, 0), 2, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atr, err := (&ATR{}).CalculateLatestATR(tt.bars, 3)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			stop, err := ATRStopPrice(50, atr, tt.multiple)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", stop)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			// 1% of 10000
+			size, err := CalculatePositionSize(10000, 1, 50, stop, &Position{MarketType: Equity})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if want, _ := decimal.NewFromString(tt.want); !size.Quantity.Equal(want) {
+				t.Errorf("quantity = %s, want %s", size.Quantity, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculatePositionSizeErrors(t *testing.T) {
+	tests := []struct {
+		name                             string
+		equity, riskPercent, entry, stop float64
+	}{
+		{"no equity", 0, 1, 50, 48},
+		{"risk above 100", 10000, 101, 50, 48},
+		{"no entry", 10000, 1, 0, 48},
+		{"stop above the entry", 10000, 1, 50, 52},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := CalculatePositionSize(tt.equity, tt.riskPercent, tt.entry, tt.stop, &Position{MarketType: Equity}); err == nil {
+				t.Fatalf("expected an error, got %+v", got)
+			}
+		})
+	}
+}
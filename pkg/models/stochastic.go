@@ -39,7 +39,8 @@ func (sto *Stochastic) calculate(marketDataList []BasicMarketData) (bool, error)
 // calculateStochasticOscillator calculates the Stochastic Oscillator
 func (sto *Stochastic) calculateStochasticOscillator(marketDataList []BasicMarketData, period int) ([]float64, []float64, error) {
 	closes, highs, lows, _ := ExtractMarketData(marketDataList)
-	if len(closes) < period || len(highs) < period || len(lows) < period {
+	// %D averages the last three %K values
+	if len(closes) < period+2 || len(highs) < period+2 || len(lows) < period+2 {
 		return nil, nil, fmt.Errorf("not enough data to calculate Stochastic Oscillator for the given period")
 	}
 
@@ -70,22 +71,20 @@ func (sto *Stochastic) calculateStochasticOscillator(marketDataList []BasicMarke
 // analyzeStochasticOscillator analyzes the Stochastic Oscillator values
 func (sto *Stochastic) Analyze(marketDataList []BasicMarketData) error {
 	status, err := sto.calculate(marketDataList)
-	latestK := sto.K[len(sto.K)-1]
-	latestD := sto.D[len(sto.D)-1]
-	var trendType TrendType = Neutral
-	var result string = fmt.Sprintf("Stochastic Oscillator is %.2f/%.2f, indicating normal market conditions.", latestK, latestD)
 	if !status {
-		trendType = Neutral
-		result = fmt.Sprintf("It is not possible to calculate SMA because: %s", err)
-		sto.TrendType = trendType
-		sto.Result = result
-		return fmt.Errorf("It is not possible to calculate SMA because: %s", err)
+		sto.TrendType = Neutral
+		sto.Result = fmt.Sprintf("It is not possible to calculate Stochastic Oscillator because: %s", err)
+		return fmt.Errorf("It is not possible to calculate Stochastic Oscillator because: %s", err)
 	}
 	if len(sto.K) == 0 || len(sto.D) == 0 {
 		sto.TrendType = Neutral
 		sto.Result = "Not enough data for Stochastic Oscillator analysis."
 		return fmt.Errorf("Not enough data for Stochastic Oscillator analysis.")
 	}
+	latestK := sto.K[len(sto.K)-1]
+	latestD := sto.D[len(sto.D)-1]
+	var trendType TrendType = Neutral
+	var result string = fmt.Sprintf("Stochastic Oscillator is %.2f/%.2f, indicating normal market conditions.", latestK, latestD)
 
 	if latestK > 80 && latestD > 80 {
 		trendType = Overbought
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+type StopType int
+
+// Define constants representing the enumerator values
+const (
+	NoStop StopType = iota
+	FixedStop
+	PercentTrailingStop
+	ATRTrailingStop
+)
+
+func (t StopType) String() string {
+	switch t {
+	case NoStop:
+		return "No Stop"
+	case FixedStop:
+		return "Fixed Stop"
+	case PercentTrailingStop:
+		return "Percent Trailing Stop"
+	case ATRTrailingStop:
+		return "ATR Trailing Stop"
+	default:
+		return "Unknown StopType"
+	}
+}
+
+// SetStop configures the stop of the position, atr is only used by the ATR trailing stop
+func (p *Position) SetStop(stopType StopType, value float64, atr float64) error {
+	switch stopType {
+	case NoStop:
+		value = 0
+	case FixedStop:
+		if value <= 0 {
+			return errors.New("stop price must be positive")
+		}
+	case PercentTrailingStop:
+		if value <= 0 || value >= 100 {
+			return errors.New("trailing percentage must be between 0 and 100")
+		}
+	case ATRTrailingStop:
+		if value <= 0 {
+			return errors.New("ATR multiple must be positive")
+		}
+		if atr <= 0 {
+			return errors.New("ATR must be positive")
+		}
+	default:
+		return errors.New("unknown stop type")
+	}
+	p.StopType = stopType
+	p.StopValue = value
+	p.StopATR = atr
+	p.HighestPrice = math.Max(p.HighestPrice, p.EntryPrice)
+	p.StopTriggered = false
+	p.StopTriggeredAt = time.Time{}
+	p.StopTriggerPrice = 0
+	p.StopPrice = p.stopFromHigh()
+	return nil
+}
+
+// stopFromHigh calculates the stop level for the highest price seen so far
+func (p *Position) stopFromHigh() float64 {
+	switch p.StopType {
+	case FixedStop:
+		return p.StopValue
+	case PercentTrailingStop:
+		return p.HighestPrice * (1 - p.StopValue/100)
+	case ATRTrailingStop:
+		return p.HighestPrice - p.StopValue*p.StopATR
+	default:
+		return 0
+	}
+}
+
+// UpdateStop ratchets the stop with a new price and reports if it moved or was breached.
+// The stop of a long position never goes down, atr refreshes the ATR of the trailing stop when positive.
+func (p *Position) UpdateStop(price float64, high float64, atr float64) (bool, bool) {
+	if p.StopType == NoStop || p.StopTriggered {
+		return false, false
+	}
+	if atr > 0 {
+		p.StopATR = atr
+	}
+	p.HighestPrice = math.Max(p.HighestPrice, math.Max(price, high))
+	moved := false
+	if candidate := p.stopFromHigh(); candidate > p.StopPrice {
+		p.StopPrice = candidate
+		moved = true
+	}
+	if price > 0 && price <= p.StopPrice {
+		p.StopTriggered = true
+		p.StopTriggeredAt = time.Now()
+		p.StopTriggerPrice = price
+		return moved, true
+	}
+	return moved, false
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Supertrend struct {
+	symbol     string
+	Supertrend []float64 // Supertrend line, aligned to the last bar
+	Uptrend    []bool    // Direction of the trend for each value of the line
+	TrendType  TrendType
+	Result     string
+}
+
+func NewSupertrend(symbol string) (*Supertrend, error) {
+	if symbol == "" {
+		return nil, errors.New("symbol cannot be empty")
+	}
+	return &Supertrend{symbol: symbol}, nil
+}
+
+func (i *Supertrend) SetIndex(indexes *Indexes) *Indexes {
+	indexes.Supertrend = *i
+	return indexes
+}
+
+// calculateSupertrend calculates the Supertrend line from the ATR bands around the median price
+func (st *Supertrend) calculateSupertrend(marketDataList []BasicMarketData, period int, multiplier float64) ([]float64, []bool, error) {
+	if len(marketDataList) <= period {
+		return nil, nil, fmt.Errorf("not enough data to calculate Supertrend for the given period")
+	}
+	atrArray, err := (&ATR{}).calculateATR(marketDataList, period)
+	if err != nil {
+		return nil, nil, err
+	}
+	offset := len(marketDataList) - len(atrArray)
+
+	line := make([]float64, len(atrArray))
+	uptrend := make([]bool, len(atrArray))
+	var finalUpper, finalLower float64
+	for k, atr := range atrArray {
+		i := k + offset
+		median := (marketDataList[i].High + marketDataList[i].Low) / 2
+		basicUpper := median + multiplier*atr
+		basicLower := median - multiplier*atr
+		if k == 0 {
+			finalUpper, finalLower = basicUpper, basicLower
+			uptrend[k] = marketDataList[i].Close > median
+		} else {
+			prevClose := marketDataList[i-1].Close
+			// The bands only tighten while the price stays inside them
+			if basicUpper < finalUpper || prevClose > finalUpper {
+				finalUpper = basicUpper
+			} else {
+				basicUpper = finalUpper
+			}
+			if basicLower > finalLower || prevClose < finalLower {
+				finalLower = basicLower
+			} else {
+				basicLower = finalLower
+			}
+			close := marketDataList[i].Close
+			switch {
+			case uptrend[k-1] && close < finalLower:
+				uptrend[k] = false
+			case !uptrend[k-1] && close > finalUpper:
+				uptrend[k] = true
+			default:
+				uptrend[k] = uptrend[k-1]
+			}
+		}
+		if uptrend[k] {
+			line[k] = finalLower
+		} else {
+			line[k] = finalUpper
+		}
+	}
+	return line, uptrend, nil
+}
+
+// Analyze analyzes the direction of the Supertrend and its last flip
+func (st *Supertrend) Analyze(marketDataList []BasicMarketData) error {
+	line, uptrend, err := st.calculateSupertrend(marketDataList, 10, 3)
+	if err != nil {
+		st.TrendType = None
+		st.Result = fmt.Sprintf("It is not possible to calculate Supertrend due to: %s", err)
+		return fmt.Errorf("It is not possible to calculate Supertrend due to: %s", err)
+	}
+	st.Supertrend = line
+	st.Uptrend = uptrend
+
+	last := len(line) - 1
+	flipped := last > 0 && uptrend[last] != uptrend[last-1]
+	switch {
+	case uptrend[last] && flipped:
+		st.TrendType = Potential_Uptrend
+		st.Result = fmt.Sprintf("Supertrend flipped to bullish, the line is at %.2f below the price.", line[last])
+	case uptrend[last]:
+		st.TrendType = Uptrend
+		st.Result = fmt.Sprintf("Supertrend is bullish, the line is at %.2f below the price.", line[last])
+	case flipped:
+		st.TrendType = Potential_Downtrend
+		st.Result = fmt.Sprintf("Supertrend flipped to bearish, the line is at %.2f above the price.", line[last])
+	default:
+		st.TrendType = Downtrend
+		st.Result = fmt.Sprintf("Supertrend is bearish, the line is at %.2f above the price.", line[last])
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// PriceLevel is a support or resistance zone built from clustered swings
+type PriceLevel struct {
+	Price     float64 `json:"price"`      // Average price of the swings in the cluster
+	Touches   int     `json:"touches"`    // Number of swings in the cluster
+	Strength  float64 `json:"strength"`   // From 0 to 1, more touches and more recent is stronger
+	LastTouch int64   `json:"last_touch"` // Timestamp of the most recent swing
+	lastIndex int
+}
+
+type SupportResistance struct {
+	symbol            string
+	Levels            []PriceLevel
+	NearestSupport    *PriceLevel
+	NearestResistance *PriceLevel
+	LastClose         float64
+	TrendType         TrendType
+	Result            string
+}
+
+func NewSupportResistance(symbol string) (*SupportResistance, error) {
+	if symbol == "" {
+		return nil, errors.New("symbol cannot be empty")
+	}
+	return &SupportResistance{symbol: symbol}, nil
+}
+
+func (i *SupportResistance) SetIndex(indexes *Indexes) *Indexes {
+	indexes.SupportResistance = *i
+	return indexes
+}
+
+// calculateLevels clusters the swings whose prices are within tolerance (a fraction of the price)
+func (sr *SupportResistance) calculateLevels(marketDataList []BasicMarketData, window int, tolerance float64) ([]PriceLevel, error) {
+	if len(marketDataList) < 2*window+1 {
+		return nil, fmt.Errorf("not enough data to detect support and resistance levels")
+	}
+	swings := FindSwings(marketDataList, window)
+	if len(swings) == 0 {
+		return nil, fmt.Errorf("no swing highs or lows found")
+	}
+	sort.Slice(swings, func(a, b int) bool { return swings[a].Price < swings[b].Price })
+
+	var levels []PriceLevel
+	var cluster []Swing
+	flush := func() {
+		level := PriceLevel{Touches: len(cluster), lastIndex: -1}
+		sum := 0.0
+		for _, s := range cluster {
+			sum += s.Price
+			if s.Index > level.lastIndex {
+				level.lastIndex = s.Index
+				level.LastTouch = s.TimeStamp
+			}
+		}
+		level.Price = sum / float64(len(cluster))
+		levels = append(levels, level)
+	}
+	for _, s := range swings {
+		if len(cluster) > 0 && s.Price-cluster[0].Price > cluster[0].Price*tolerance {
+			flush()
+			cluster = nil
+		}
+		cluster = append(cluster, s)
+	}
+	flush()
+
+	maxTouches := 0
+	for _, l := range levels {
+		if l.Touches > maxTouches {
+			maxTouches = l.Touches
+		}
+	}
+	for i := range levels {
+		recency := float64(levels[i].lastIndex+1) / float64(len(marketDataList))
+		levels[i].Strength = 0.7*float64(levels[i].Touches)/float64(maxTouches) + 0.3*recency
+	}
+	return levels, nil
+}
+
+// Analyze detects the levels and reports the nearest support and resistance to the last close
+func (sr *SupportResistance) Analyze(marketDataList []BasicMarketData) error {
+	levels, err := sr.calculateLevels(marketDataList, 5, 0.015)
+	if err != nil {
+		sr.TrendType = None
+		sr.Result = fmt.Sprintf("It is not possible to detect support and resistance due to: %s", err)
+		return fmt.Errorf("It is not possible to detect support and resistance due to: %s", err)
+	}
+	sr.Levels = levels
+	sr.LastClose = marketDataList[len(marketDataList)-1].Close
+	sr.NearestSupport, sr.NearestResistance = nil, nil
+	for i := range levels {
+		level := &levels[i]
+		if level.Price <= sr.LastClose && (sr.NearestSupport == nil || level.Price > sr.NearestSupport.Price) {
+			sr.NearestSupport = level
+		}
+		if level.Price > sr.LastClose && (sr.NearestResistance == nil || level.Price < sr.NearestResistance.Price) {
+			sr.NearestResistance = level
+		}
+	}
+
+	supportDistance, resistanceDistance := math.Inf(1), math.Inf(1)
+	if sr.NearestSupport != nil {
+		supportDistance = (sr.LastClose - sr.NearestSupport.Price) / sr.LastClose
+	}
+	if sr.NearestResistance != nil {
+		resistanceDistance = (sr.NearestResistance.Price - sr.LastClose) / sr.LastClose
+	}
+	switch {
+	case supportDistance <= 0.02 && supportDistance <= resistanceDistance:
+		sr.TrendType = Potential_Uptrend
+		sr.Result = fmt.Sprintf("Price %.2f is close to support at %.2f (%d touches), a bounce is possible.", sr.LastClose, sr.NearestSupport.Price, sr.NearestSupport.Touches)
+	case resistanceDistance <= 0.02:
+		sr.TrendType = Potential_Downtrend
+		sr.Result = fmt.Sprintf("Price %.2f is close to resistance at %.2f (%d touches), a rejection is possible.", sr.LastClose, sr.NearestResistance.Price, sr.NearestResistance.Touches)
+	case sr.NearestSupport == nil:
+		sr.TrendType = Downtrend
+		sr.Result = fmt.Sprintf("Price %.2f is below every detected level, the nearest resistance is %.2f.", sr.LastClose, sr.NearestResistance.Price)
+	case sr.NearestResistance == nil:
+		sr.TrendType = Uptrend
+		sr.Result = fmt.Sprintf("Price %.2f is above every detected level, the nearest support is %.2f.", sr.LastClose, sr.NearestSupport.Price)
+	default:
+		sr.TrendType = Neutral
+		sr.Result = fmt.Sprintf("Price %.2f is between support at %.2f and resistance at %.2f.", sr.LastClose, sr.NearestSupport.Price, sr.NearestResistance.Price)
+	}
+	return nil
+}
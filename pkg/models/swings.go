@@ -0,0 +1,54 @@
+package models
+
+// Swing is a local high or low of a series
+type Swing struct {
+	Index     int     `json:"index"`     // Position of the bar in the series
+	Price     float64 `json:"price"`     // Value of the series at the swing
+	High      bool    `json:"high"`      // Swing high when true, swing low otherwise
+	TimeStamp int64   `json:"timestamp"` // Timestamp of the bar
+}
+
+// findPivots returns the indexes of the values that are the highest (or lowest) of the window around them
+func findPivots(values []float64, window int, high bool) []int {
+	var pivots []int
+	for i := window; i < len(values)-window; i++ {
+		isPivot := true
+		for j := i - window; j <= i+window && isPivot; j++ {
+			if j == i {
+				continue
+			}
+			if high && values[j] > values[i] || !high && values[j] < values[i] {
+				isPivot = false
+			}
+			// Equal values only count once, for the first bar
+			if values[j] == values[i] && j < i {
+				isPivot = false
+			}
+		}
+		if isPivot {
+			pivots = append(pivots, i)
+		}
+	}
+	return pivots
+}
+
+// FindSwings returns the swing highs and lows of the bars in chronological order
+func FindSwings(marketDataList []BasicMarketData, window int) []Swing {
+	_, highs, lows, _ := ExtractMarketData(marketDataList)
+	highPivots := findPivots(highs, window, true)
+	lowPivots := findPivots(lows, window, false)
+	swings := make([]Swing, 0, len(highPivots)+len(lowPivots))
+	h, l := 0, 0
+	for h < len(highPivots) || l < len(lowPivots) {
+		if l >= len(lowPivots) || h < len(highPivots) && highPivots[h] <= lowPivots[l] {
+			i := highPivots[h]
+			swings = append(swings, Swing{Index: i, Price: highs[i], High: true, TimeStamp: marketDataList[i].TimeStamp})
+			h++
+		} else {
+			i := lowPivots[l]
+			swings = append(swings, Swing{Index: i, Price: lows[i], High: false, TimeStamp: marketDataList[i].TimeStamp})
+			l++
+		}
+	}
+	return swings
+}
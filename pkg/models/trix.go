@@ -0,0 +1,94 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+type TRIX struct {
+	symbol     string
+	TRIXArray  []float64
+	TRIXSignal []float64
+	TrendType  TrendType
+	Result     string
+}
+
+func NewTRIX(symbol string) (*TRIX, error) {
+	if symbol == "" {
+		return nil, errors.New("symbol cannot be empty")
+	}
+	return &TRIX{symbol: symbol}, nil
+}
+
+func (i *TRIX) SetIndex(indexes *Indexes) *Indexes {
+	indexes.TRIX = *i
+	return indexes
+}
+
+// calculateTRIX calculates the percentage change of a triple smoothed EMA and its signal line
+func (t *TRIX) calculateTRIX(marketDataList []BasicMarketData, period int, signalPeriod int) ([]float64, []float64, error) {
+	ema := &EMA{}
+	single, err := ema.CalculateEMA(marketDataList, period)
+	if err != nil {
+		return nil, nil, err
+	}
+	double, err := ema.CalculateEMAFromMACD(single, period)
+	if err != nil {
+		return nil, nil, err
+	}
+	triple, err := ema.CalculateEMAFromMACD(double, period)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(triple) < 2 {
+		return nil, nil, fmt.Errorf("not enough data to calculate TRIX for the given period")
+	}
+
+	trix := make([]float64, len(triple)-1)
+	for i := 1; i < len(triple); i++ {
+		trix[i-1] = 100 * (triple[i] - triple[i-1]) / triple[i-1]
+	}
+	signal, err := ema.CalculateEMAFromMACD(trix, signalPeriod)
+	if err != nil {
+		return nil, nil, err
+	}
+	return trix, signal, nil
+}
+
+// Analyze analyzes TRIX against zero and its signal line
+func (t *TRIX) Analyze(marketDataList []BasicMarketData) error {
+	trix, signal, err := t.calculateTRIX(marketDataList, 15, 9)
+	if err != nil {
+		t.TrendType = None
+		t.Result = fmt.Sprintf("It is not possible to calculate TRIX due to: %s", err)
+		return fmt.Errorf("It is not possible to calculate TRIX due to: %s", err)
+	}
+	t.TRIXArray = trix
+	t.TRIXSignal = signal
+	if len(signal) < 2 {
+		t.TrendType = None
+		t.Result = "Not enough data for TRIX analysis."
+		return fmt.Errorf("Not enough data for TRIX analysis.")
+	}
+
+	latestTRIX, prevTRIX := trix[len(trix)-1], trix[len(trix)-2]
+	latestSignal, prevSignal := signal[len(signal)-1], signal[len(signal)-2]
+	switch {
+	case latestTRIX > latestSignal && prevTRIX <= prevSignal:
+		t.TrendType = Potential_Uptrend
+		t.Result = fmt.Sprintf("Bullish crossover detected. TRIX (%.4f) has crossed above the Signal line.", latestTRIX)
+	case latestTRIX < latestSignal && prevTRIX >= prevSignal:
+		t.TrendType = Potential_Downtrend
+		t.Result = fmt.Sprintf("Bearish crossover detected. TRIX (%.4f) has crossed below the Signal line.", latestTRIX)
+	case latestTRIX > 0 && latestTRIX > latestSignal:
+		t.TrendType = Uptrend
+		t.Result = fmt.Sprintf("TRIX is %.4f, positive and above the Signal line, indicating an uptrend.", latestTRIX)
+	case latestTRIX < 0 && latestTRIX < latestSignal:
+		t.TrendType = Downtrend
+		t.Result = fmt.Sprintf("TRIX is %.4f, negative and below the Signal line, indicating a downtrend.", latestTRIX)
+	default:
+		t.TrendType = Neutral
+		t.Result = fmt.Sprintf("TRIX is %.4f and the Signal line is %.4f, the trend is not clear.", latestTRIX, latestSignal)
+	}
+	return nil
+}
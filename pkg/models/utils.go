@@ -1,25 +1,9 @@
 package models
 
-import "time"
+import "github.com/megajandrox/go-finance-api/pkg/calendar"
 
-// extractDailyCloses extrae el precio de cierre diario de los datos intradía
-func ExtractDailyCloses(data []BasicMarketData) []BasicMarketData {
-	dailyCloses := []BasicMarketData{}
-	var currentDay time.Time
-	var lastClose BasicMarketData
-
-	for _, entry := range data {
-		entryTime := time.Unix(entry.TimeStamp, 0)
-		if !currentDay.IsZero() && entryTime.Day() != currentDay.Day() {
-			dailyCloses = append(dailyCloses, lastClose)
-		}
-		currentDay = entryTime
-		lastClose = entry
-	}
-	// Agregar el último precio de cierre del último día
-	if !currentDay.IsZero() {
-		dailyCloses = append(dailyCloses, lastClose)
-	}
-
-	return dailyCloses
+// ExtractDailyCloses aggregates intraday bars into one bar per trading day of the exchange
+func ExtractDailyCloses(data []BasicMarketData, exchange *calendar.Exchange) []BasicMarketData {
+	dailyBars, _ := Resample(data, "1d", exchange)
+	return dailyBars
 }
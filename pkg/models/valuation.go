@@ -0,0 +1,70 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+/*
+ * Open holdings of a symbol valued at the latest price
+ */
+type HoldingValuation struct {
+	Symbol         string          `json:"symbol"`
+	MarketType     string          `json:"market_type"`
+	Quantity       decimal.Decimal `json:"quantity"`        // Net quantity, negative when it is net short
+	LongQuantity   decimal.Decimal `json:"long_quantity"`   // Quantity of the long positions
+	ShortQuantity  decimal.Decimal `json:"short_quantity"`  // Quantity owed by the shorts
+	Currency       string          `json:"currency"`        // Currency of the price
+	Price          float64         `json:"price"`           // Latest price
+	MarketValue    float64         `json:"market_value"`    // Value in the currency of the price
+	ConversionRate float64         `json:"conversion_rate"` // Units of the reporting currency per unit of Currency
+	Value          float64         `json:"value"`           // Value in the reporting currency
+	LongValue      float64         `json:"long_value"`      // Value of the longs in the reporting currency
+	ShortValue     float64         `json:"short_value"`     // Value of the shares owed by the shorts in the reporting currency
+}
+
+/*
+ * Balance of a cash account in the reporting currency
+ */
+type CashValuation struct {
+	Currency       string  `json:"currency"`
+	Balance        float64 `json:"balance"`
+	ConversionRate float64 `json:"conversion_rate"` // Units of the reporting currency per unit of Currency
+	Value          float64 `json:"value"`           // Balance in the reporting currency
+}
+
+/*
+ * Holdings and cash converted into one reporting currency
+ */
+type PortfolioValuation struct {
+	Currency      string             `json:"currency"` // Reporting currency
+	Holdings      []HoldingValuation `json:"holdings"`
+	Cash          []CashValuation    `json:"cash"`
+	HoldingsValue float64            `json:"holdings_value"` // Net value, longs minus shorts
+	LongExposure  float64            `json:"long_exposure"`  // Value of the long holdings
+	ShortExposure float64            `json:"short_exposure"` // Value of the shares owed by the shorts
+	GrossExposure float64            `json:"gross_exposure"` // Longs plus shorts
+	CashValue     float64            `json:"cash_value"`
+	Total         float64            `json:"total"`
+	Unpriced      []string           `json:"unpriced,omitempty"` // Symbols and currencies left out, without price or conversion
+}
+
+// AddHolding converts the market values of the holding and of its longs and shorts, in the currency of the price,
+// at the rate and adds them to the totals
+func (v *PortfolioValuation) AddHolding(holding HoldingValuation, longValue float64, shortValue float64, rate float64) {
+	holding.ConversionRate = rate
+	holding.Value = holding.MarketValue * rate
+	holding.LongValue = longValue * rate
+	holding.ShortValue = shortValue * rate
+	v.Holdings = append(v.Holdings, holding)
+	v.HoldingsValue += holding.Value
+	v.LongExposure += holding.LongValue
+	v.ShortExposure += holding.ShortValue
+	v.GrossExposure = v.LongExposure + v.ShortExposure
+	v.Total += holding.Value
+}
+
+// AddCash converts the balance at the rate and adds it to the totals
+func (v *PortfolioValuation) AddCash(currency string, balance float64, rate float64) {
+	cash := CashValuation{Currency: currency, Balance: balance, ConversionRate: rate, Value: balance * rate}
+	v.Cash = append(v.Cash, cash)
+	v.CashValue += cash.Value
+	v.Total += cash.Value
+}
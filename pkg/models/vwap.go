@@ -0,0 +1,144 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/megajandrox/go-finance-api/pkg/calendar"
+)
+
+// VWAPPoint holds the VWAP of a bar and its standard deviation bands
+type VWAPPoint struct {
+	TimeStamp int64   `json:"timestamp"`
+	VWAP      float64 `json:"vwap"`
+	Upper1    float64 `json:"upper_1"` // VWAP + 1 standard deviation
+	Lower1    float64 `json:"lower_1"` // VWAP - 1 standard deviation
+	Upper2    float64 `json:"upper_2"` // VWAP + 2 standard deviations
+	Lower2    float64 `json:"lower_2"` // VWAP - 2 standard deviations
+}
+
+type VWAP struct {
+	symbol    string
+	Anchor    int64       // Unix timestamp where the anchored VWAP starts, 0 for none
+	Session   []VWAPPoint // VWAP restarted on every session
+	Anchored  []VWAPPoint // VWAP accumulated from the anchor
+	TrendType TrendType
+	Result    string
+}
+
+func NewVWAP(symbol string) (*VWAP, error) {
+	if symbol == "" {
+		return nil, errors.New("symbol cannot be empty")
+	}
+	return &VWAP{symbol: symbol}, nil
+}
+
+// NewAnchoredVWAP creates a VWAP that also accumulates from the anchor (e.g. an earnings date or a position entry)
+func NewAnchoredVWAP(symbol string, anchor time.Time) (*VWAP, error) {
+	vwap, err := NewVWAP(symbol)
+	if err != nil {
+		return nil, err
+	}
+	vwap.Anchor = anchor.Unix()
+	return vwap, nil
+}
+
+func (i *VWAP) SetIndex(indexes *Indexes) *Indexes {
+	indexes.VWAP = *i
+	return indexes
+}
+
+// calculateVWAP accumulates the typical price weighted by volume, restarting when reset says so
+func (v *VWAP) calculateVWAP(marketDataList []BasicMarketData, reset func(previous, current BasicMarketData) bool) []VWAPPoint {
+	points := make([]VWAPPoint, len(marketDataList))
+	var sumVolume, sumPriceVolume, sumSquaredPriceVolume float64
+	for i, d := range marketDataList {
+		if i > 0 && reset(marketDataList[i-1], d) {
+			sumVolume, sumPriceVolume, sumSquaredPriceVolume = 0, 0, 0
+		}
+		typicalPrice := (d.High + d.Low + d.Close) / 3
+		volume := float64(d.Volume)
+		sumVolume += volume
+		sumPriceVolume += typicalPrice * volume
+		sumSquaredPriceVolume += typicalPrice * typicalPrice * volume
+
+		point := VWAPPoint{TimeStamp: d.TimeStamp, VWAP: typicalPrice}
+		deviation := 0.0
+		if sumVolume > 0 {
+			point.VWAP = sumPriceVolume / sumVolume
+			deviation = math.Sqrt(math.Max(sumSquaredPriceVolume/sumVolume-point.VWAP*point.VWAP, 0))
+		}
+		point.Upper1, point.Lower1 = point.VWAP+deviation, point.VWAP-deviation
+		point.Upper2, point.Lower2 = point.VWAP+2*deviation, point.VWAP-2*deviation
+		points[i] = point
+	}
+	return points
+}
+
+// sameSession tells if two bars belong to the same trading day of the exchange
+func sameSession(exchange *calendar.Exchange, previous, current BasicMarketData) bool {
+	return exchange.TradingDay(previous.TimeStamp).Equal(exchange.TradingDay(current.TimeStamp))
+}
+
+func (v *VWAP) calculate(marketDataList []BasicMarketData) error {
+	if len(marketDataList) < 2 {
+		return fmt.Errorf("not enough data to calculate VWAP")
+	}
+	if marketDataList[len(marketDataList)-1].TimeStamp-marketDataList[len(marketDataList)-2].TimeStamp >= 24*60*60 {
+		return fmt.Errorf("VWAP is only calculated for intraday intervals")
+	}
+	exchange := calendar.ForSymbol(v.symbol)
+	v.Session = v.calculateVWAP(marketDataList, func(previous, current BasicMarketData) bool {
+		return !sameSession(exchange, previous, current)
+	})
+	v.Anchored = nil
+	if v.Anchor != 0 {
+		start := -1
+		for i, d := range marketDataList {
+			if d.TimeStamp >= v.Anchor {
+				start = i
+				break
+			}
+		}
+		if start < 0 {
+			return fmt.Errorf("the anchor is after the last bar")
+		}
+		v.Anchored = v.calculateVWAP(marketDataList[start:], func(previous, current BasicMarketData) bool { return false })
+	}
+	return nil
+}
+
+// Analyze compares the last close with the session VWAP and its bands
+func (v *VWAP) Analyze(marketDataList []BasicMarketData) error {
+	if err := v.calculate(marketDataList); err != nil {
+		v.TrendType = None
+		v.Result = fmt.Sprintf("It is not possible to calculate VWAP due to: %s", err)
+		return fmt.Errorf("It is not possible to calculate VWAP due to: %s", err)
+	}
+	close := marketDataList[len(marketDataList)-1].Close
+	last := v.Session[len(v.Session)-1]
+	switch {
+	case close > last.Upper2:
+		v.TrendType = Overbought
+		v.Result = fmt.Sprintf("Price %.2f is above the upper 2σ band (%.2f) of the session VWAP %.2f, indicating the asset is overextended.", close, last.Upper2, last.VWAP)
+	case close < last.Lower2:
+		v.TrendType = Oversold
+		v.Result = fmt.Sprintf("Price %.2f is below the lower 2σ band (%.2f) of the session VWAP %.2f, indicating the asset is overextended.", close, last.Lower2, last.VWAP)
+	case close > last.VWAP:
+		v.TrendType = Potential_Uptrend
+		v.Result = fmt.Sprintf("Price %.2f is above the session VWAP %.2f, buyers are in control.", close, last.VWAP)
+	case close < last.VWAP:
+		v.TrendType = Potential_Downtrend
+		v.Result = fmt.Sprintf("Price %.2f is below the session VWAP %.2f, sellers are in control.", close, last.VWAP)
+	default:
+		v.TrendType = Neutral
+		v.Result = fmt.Sprintf("Price %.2f is at the session VWAP.", close)
+	}
+	if len(v.Anchored) > 0 {
+		anchored := v.Anchored[len(v.Anchored)-1]
+		v.Result += fmt.Sprintf(" The anchored VWAP is %.2f.", anchored.VWAP)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+type WilliamsR struct {
+	symbol          string
+	LatestWilliamsR float64
+	TrendType       TrendType
+	Result          string
+}
+
+func NewWilliamsR(symbol string) (*WilliamsR, error) {
+	if symbol == "" {
+		return nil, errors.New("symbol cannot be empty")
+	}
+	return &WilliamsR{symbol: symbol}, nil
+}
+
+func (i *WilliamsR) SetIndex(indexes *Indexes) *Indexes {
+	indexes.WilliamsR = *i
+	return indexes
+}
+
+// calculateWilliamsR calculates the Williams %R for a given period, from -100 to 0
+func (w *WilliamsR) calculateWilliamsR(marketDataList []BasicMarketData, period int) ([]float64, error) {
+	closes, highs, lows, _ := ExtractMarketData(marketDataList)
+	if len(closes) < period {
+		return nil, fmt.Errorf("not enough data to calculate Williams %%R for the given period")
+	}
+
+	values := make([]float64, len(closes)-period+1)
+	for i := period - 1; i < len(closes); i++ {
+		high, low := highs[i], lows[i]
+		for j := i - period + 1; j <= i; j++ {
+			high = max(high, highs[j])
+			low = min(low, lows[j])
+		}
+		if high == low {
+			values[i-period+1] = -50
+			continue
+		}
+		values[i-period+1] = -100 * (high - closes[i]) / (high - low)
+	}
+
+	return values, nil
+}
+
+// Analyze analyzes the Williams %R value for overbought or oversold conditions
+func (w *WilliamsR) Analyze(marketDataList []BasicMarketData) error {
+	values, err := w.calculateWilliamsR(marketDataList, 14)
+	if err != nil {
+		w.TrendType = None
+		w.Result = fmt.Sprintf("It is not possible to calculate Williams %%R due to: %s", err)
+		return fmt.Errorf("It is not possible to calculate Williams %%R due to: %s", err)
+	}
+	w.LatestWilliamsR = values[len(values)-1]
+
+	switch {
+	case w.LatestWilliamsR > -20:
+		w.TrendType = Overbought
+		w.Result = fmt.Sprintf("Williams %%R is %.2f, indicating the asset is overbought.", w.LatestWilliamsR)
+	case w.LatestWilliamsR < -80:
+		w.TrendType = Oversold
+		w.Result = fmt.Sprintf("Williams %%R is %.2f, indicating the asset is oversold.", w.LatestWilliamsR)
+	default:
+		w.TrendType = Neutral
+		w.Result = fmt.Sprintf("Williams %%R is %.2f, indicating normal market conditions.", w.LatestWilliamsR)
+	}
+	return nil
+}
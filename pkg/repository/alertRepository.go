@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"github.com/megajandrox/go-finance-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type AlertRepository interface {
+	Create(alert *models.Alert) error
+	GetAll() ([]models.Alert, error)
+	GetActive() ([]models.Alert, error)
+	GetByID(id uint) (*models.Alert, error)
+	Update(alert *models.Alert) error
+	Delete(id uint) error
+	CreateEvent(event *models.AlertEvent) error
+	UpdateEvent(event *models.AlertEvent) error
+	GetEvents(alertID uint, symbol string, limit int) ([]models.AlertEvent, error)
+}
+
+type alertRepository struct {
+	db *gorm.DB
+}
+
+func NewAlertRepository(db *gorm.DB) AlertRepository {
+	return &alertRepository{db}
+}
+
+func (r *alertRepository) Create(alert *models.Alert) error {
+	return r.db.Create(alert).Error
+}
+
+func (r *alertRepository) GetAll() ([]models.Alert, error) {
+	var alerts []models.Alert
+	err := r.db.Find(&alerts).Error
+	return alerts, err
+}
+
+func (r *alertRepository) GetActive() ([]models.Alert, error) {
+	var alerts []models.Alert
+	err := r.db.Where("active = ?", true).Find(&alerts).Error
+	return alerts, err
+}
+
+func (r *alertRepository) GetByID(id uint) (*models.Alert, error) {
+	var alert models.Alert
+	err := r.db.First(&alert, id).Error
+	return &alert, err
+}
+
+func (r *alertRepository) Update(alert *models.Alert) error {
+	return r.db.Save(alert).Error
+}
+
+func (r *alertRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Alert{}, id).Error
+}
+
+func (r *alertRepository) CreateEvent(event *models.AlertEvent) error {
+	return r.db.Create(event).Error
+}
+
+func (r *alertRepository) UpdateEvent(event *models.AlertEvent) error {
+	return r.db.Save(event).Error
+}
+
+// GetEvents returns the most recent events, optionally filtered by alert and symbol
+func (r *alertRepository) GetEvents(alertID uint, symbol string, limit int) ([]models.AlertEvent, error) {
+	var events []models.AlertEvent
+	query := r.db.Order("triggered_at desc")
+	if alertID != 0 {
+		query = query.Where("alert_id = ?", alertID)
+	}
+	if symbol != "" {
+		query = query.Where("symbol = ?", symbol)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&events).Error
+	return events, err
+}
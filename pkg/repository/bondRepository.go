@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"github.com/megajandrox/go-finance-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type BondRepository interface {
+	Create(bond *models.BondIssue) error
+	GetAll() ([]models.BondIssue, error)
+	GetBySymbol(symbol string) (*models.BondIssue, error)
+}
+
+type bondRepository struct {
+	db *gorm.DB
+}
+
+func NewBondRepository(db *gorm.DB) BondRepository {
+	return &bondRepository{db}
+}
+
+// orderedCashFlows preloads the schedule oldest payment first
+func orderedCashFlows(db *gorm.DB) *gorm.DB {
+	return db.Order("payment_date")
+}
+
+func (r *bondRepository) Create(bond *models.BondIssue) error {
+	return r.db.Create(bond).Error
+}
+
+func (r *bondRepository) GetAll() ([]models.BondIssue, error) {
+	var bonds []models.BondIssue
+	err := r.db.Preload("CashFlows", orderedCashFlows).Order("symbol").Find(&bonds).Error
+	return bonds, err
+}
+
+func (r *bondRepository) GetBySymbol(symbol string) (*models.BondIssue, error) {
+	var bond models.BondIssue
+	err := r.db.Preload("CashFlows", orderedCashFlows).Where("symbol = ?", symbol).First(&bond).Error
+	return &bond, err
+}
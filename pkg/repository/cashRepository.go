@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/megajandrox/go-finance-api/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CashRepository interface {
+	Create(account *models.CashAccount) error
+	GetAll() ([]models.CashAccount, error)
+	GetByCurrency(currency string) (*models.CashAccount, error)
+	GetByCurrencyForUpdate(currency string) (*models.CashAccount, error)
+	Update(account *models.CashAccount) error
+	UpdateSettings(account *models.CashAccount) error
+	CreateTransaction(transaction *models.CashTransaction) error
+	GetTransactions(accountID uint, from time.Time, to time.Time) ([]models.CashTransaction, error)
+	Transaction(fn func(repo CashRepository) error) error
+}
+
+type cashRepository struct {
+	db *gorm.DB
+}
+
+func NewCashRepository(db *gorm.DB) CashRepository {
+	return &cashRepository{db}
+}
+
+func (r *cashRepository) Create(account *models.CashAccount) error {
+	return r.db.Create(account).Error
+}
+
+func (r *cashRepository) GetAll() ([]models.CashAccount, error) {
+	var accounts []models.CashAccount
+	err := r.db.Order("currency").Find(&accounts).Error
+	return accounts, err
+}
+
+func (r *cashRepository) GetByCurrency(currency string) (*models.CashAccount, error) {
+	var account models.CashAccount
+	err := r.db.Where("currency = ?", currency).First(&account).Error
+	return &account, err
+}
+
+// GetByCurrencyForUpdate returns the account locked until the transaction ends, so the concurrent movements
+// wait for each other instead of overwriting the balance
+func (r *cashRepository) GetByCurrencyForUpdate(currency string) (*models.CashAccount, error) {
+	var account models.CashAccount
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("currency = ?", currency).First(&account).Error
+	return &account, err
+}
+
+func (r *cashRepository) Update(account *models.CashAccount) error {
+	return r.db.Save(account).Error
+}
+
+// UpdateSettings stores the settings of the account without touching its balance
+func (r *cashRepository) UpdateSettings(account *models.CashAccount) error {
+	return r.db.Model(account).Update("allow_overdraft", account.AllowOverdraft).Error
+}
+
+func (r *cashRepository) CreateTransaction(transaction *models.CashTransaction) error {
+	return r.db.Create(transaction).Error
+}
+
+// GetTransactions returns the movements of the account between from and to, oldest first; zero times are not filtered
+func (r *cashRepository) GetTransactions(accountID uint, from time.Time, to time.Time) ([]models.CashTransaction, error) {
+	var transactions []models.CashTransaction
+	query := r.db.Where("cash_account_id = ?", accountID)
+	if !from.IsZero() {
+		query = query.Where("occurred_at >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("occurred_at <= ?", to)
+	}
+	err := query.Order("occurred_at, id").Find(&transactions).Error
+	return transactions, err
+}
+
+// Transaction runs fn with the repository bound to a transaction, a savepoint when it is already in one
+func (r *cashRepository) Transaction(fn func(repo CashRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(NewCashRepository(tx))
+	})
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/megajandrox/go-finance-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type CorporateActionRepository interface {
+	Create(action *models.CorporateAction) error
+	GetAll() ([]models.CorporateAction, error)
+	GetBySymbol(symbol string) ([]models.CorporateAction, error)
+	GetPending(until time.Time) ([]models.CorporateAction, error)
+	Update(action *models.CorporateAction) error
+	Claim(id uint, appliedAt time.Time) (bool, error)
+}
+
+type corporateActionRepository struct {
+	db *gorm.DB
+}
+
+func NewCorporateActionRepository(db *gorm.DB) CorporateActionRepository {
+	return &corporateActionRepository{db}
+}
+
+func (r *corporateActionRepository) Create(action *models.CorporateAction) error {
+	return r.db.Create(action).Error
+}
+
+func (r *corporateActionRepository) GetAll() ([]models.CorporateAction, error) {
+	var actions []models.CorporateAction
+	err := r.db.Order("ex_date").Find(&actions).Error
+	return actions, err
+}
+
+func (r *corporateActionRepository) GetBySymbol(symbol string) ([]models.CorporateAction, error) {
+	var actions []models.CorporateAction
+	err := r.db.Where("symbol = ?", symbol).Order("ex_date").Find(&actions).Error
+	return actions, err
+}
+
+// GetPending returns the actions not applied yet whose ex-date is not after 'until', oldest first
+func (r *corporateActionRepository) GetPending(until time.Time) ([]models.CorporateAction, error) {
+	var actions []models.CorporateAction
+	err := r.db.Where("applied = ? AND ex_date <= ?", false, until).Order("ex_date").Find(&actions).Error
+	return actions, err
+}
+
+func (r *corporateActionRepository) Update(action *models.CorporateAction) error {
+	return r.db.Save(action).Error
+}
+
+// Claim marks the action as applied only if it was still pending, false means another run already claimed it
+func (r *corporateActionRepository) Claim(id uint, appliedAt time.Time) (bool, error) {
+	result := r.db.Model(&models.CorporateAction{}).
+		Where("id = ? AND applied = ?", id, false).
+		Updates(map[string]interface{}{"applied": true, "applied_at": appliedAt})
+	return result.RowsAffected == 1, result.Error
+}
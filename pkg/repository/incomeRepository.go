@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"github.com/megajandrox/go-finance-api/pkg/models"
+	"gorm.io/gorm"
+)
+
+type IncomeRepository interface {
+	Create(income *models.Income) error
+	GetByAsset(assetID uint) ([]models.Income, error)
+}
+
+type incomeRepository struct {
+	db *gorm.DB
+}
+
+func NewIncomeRepository(db *gorm.DB) IncomeRepository {
+	return &incomeRepository{db}
+}
+
+func (r *incomeRepository) Create(income *models.Income) error {
+	return r.db.Create(income).Error
+}
+
+func (r *incomeRepository) GetByAsset(assetID uint) ([]models.Income, error) {
+	var incomes []models.Income
+	err := r.db.Where("asset_id = ?", assetID).Order("paid_at").Find(&incomes).Error
+	return incomes, err
+}
@@ -1,15 +1,24 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/megajandrox/go-finance-api/pkg/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PositionRepository interface {
 	Create(position *models.Position) error
 	GetAll() ([]models.Position, error)
 	GetByID(id uint) (*models.Position, error)
+	GetByIDForUpdate(id uint) (*models.Position, error)
+	GetByAsset(assetID uint) ([]models.Position, error)
+	GetWithActiveStop() ([]models.Position, error)
+	GetOpenShorts() ([]models.Position, error)
 	Update(position *models.Position) error
+	AccrueBorrowFees(position *models.Position, accruedAt time.Time) (bool, error)
+	UpdateStop(position *models.Position) (bool, error)
 	Delete(id uint) error
 }
 
@@ -37,10 +46,64 @@ func (r *positionRepository) GetByID(id uint) (*models.Position, error) {
 	return &position, err
 }
 
+// GetByIDForUpdate returns the position locked until the transaction ends, so the concurrent trades and
+// corporate actions wait for each other instead of overwriting the quantity
+func (r *positionRepository) GetByIDForUpdate(id uint) (*models.Position, error) {
+	var position models.Position
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&position, id).Error
+	return &position, err
+}
+
+func (r *positionRepository) GetByAsset(assetID uint) ([]models.Position, error) {
+	var positions []models.Position
+	err := r.db.Where("asset_id = ?", assetID).Find(&positions).Error
+	return positions, err
+}
+
+// GetWithActiveStop returns the open long positions whose stop was not breached yet
+func (r *positionRepository) GetWithActiveStop() ([]models.Position, error) {
+	var positions []models.Position
+	err := r.db.Where("stop_type <> ? AND stop_triggered = ? AND position_type = ? AND quantity > 0", models.NoStop, false, models.Bought).Find(&positions).Error
+	return positions, err
+}
+
+func (r *positionRepository) GetOpenShorts() ([]models.Position, error) {
+	var positions []models.Position
+	err := r.db.Where("position_type = ? AND quantity > 0", models.Shorted).Find(&positions).Error
+	return positions, err
+}
+
 func (r *positionRepository) Update(position *models.Position) error {
 	return r.db.Save(position).Error
 }
 
+// AccrueBorrowFees stores only the borrow fees of the short, while it is open and was not accrued after
+// accruedAt, so a cover that ran in between is not overwritten. False means the position was not written.
+func (r *positionRepository) AccrueBorrowFees(position *models.Position, accruedAt time.Time) (bool, error) {
+	result := r.db.Model(&models.Position{}).
+		Where("id = ? AND position_type = ? AND borrow_accrued_at = ?", position.ID, models.Shorted, accruedAt).
+		Updates(map[string]interface{}{"borrow_fees": position.BorrowFees, "borrow_accrued_at": position.BorrowAccruedAt})
+	return result.RowsAffected == 1, result.Error
+}
+
+// UpdateStop stores only the stop of the position, while it keeps the type and quantity it was read with,
+// so a sale, cover or split that ran in between is not overwritten. False means the position was not written.
+func (r *positionRepository) UpdateStop(position *models.Position) (bool, error) {
+	result := r.db.Model(&models.Position{}).
+		Where("id = ? AND position_type = ? AND quantity = ?", position.ID, position.PositionType, position.Quantity).
+		Updates(map[string]interface{}{
+			"stop_type":          position.StopType,
+			"stop_value":         position.StopValue,
+			"stop_atr":           position.StopATR,
+			"stop_price":         position.StopPrice,
+			"highest_price":      position.HighestPrice,
+			"stop_triggered":     position.StopTriggered,
+			"stop_triggered_at":  position.StopTriggeredAt,
+			"stop_trigger_price": position.StopTriggerPrice,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
 func (r *positionRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Position{}, id).Error
 }
@@ -0,0 +1,36 @@
+package repository
+
+import "gorm.io/gorm"
+
+// Repositories are the repositories bound to the transaction of UnitOfWork.Transaction
+type Repositories struct {
+	Positions        PositionRepository
+	Cash             CashRepository
+	Incomes          IncomeRepository
+	CorporateActions CorporateActionRepository
+}
+
+// UnitOfWork runs several writes in one database transaction
+type UnitOfWork interface {
+	// Transaction commits when fn returns nil and rolls everything back otherwise
+	Transaction(fn func(repos Repositories) error) error
+}
+
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) UnitOfWork {
+	return &unitOfWork{db}
+}
+
+func (u *unitOfWork) Transaction(fn func(repos Repositories) error) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		return fn(Repositories{
+			Positions:        NewPositionRepository(tx),
+			Cash:             NewCashRepository(tx),
+			Incomes:          NewIncomeRepository(tx),
+			CorporateActions: NewCorporateActionRepository(tx),
+		})
+	})
+}
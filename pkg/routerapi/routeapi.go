@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/megajandrox/go-finance-api/pkg/handlers"
 	"github.com/megajandrox/go-finance-api/pkg/repository"
+	"github.com/megajandrox/go-finance-api/pkg/services"
 )
 
 func QuoteRoutes(v1 *gin.RouterGroup) {
@@ -15,25 +16,114 @@ func QuoteRoutes(v1 *gin.RouterGroup) {
 	}
 }
 
-func IndexRoutes(v1 *gin.RouterGroup) {
+func IndexRoutes(v1 *gin.RouterGroup, repo repository.PositionRepository, repo2 repository.CorporateActionRepository) {
 	quoteGroup := v1.Group("/index")
 	{
 
-		quoteGroup.GET("/:symbol", handlers.GetIndex)
+		quoteGroup.GET("/:symbol", handlers.GetIndex(repo2))
+		quoteGroup.GET("/:symbol/vwap", handlers.GetVWAP(repo, repo2))
 
 	}
 }
 
-func AssetRoutes(v1 *gin.RouterGroup, repo repository.AssetRepository, repo2 repository.PositionRepository) {
+func RuleRoutes(v1 *gin.RouterGroup, repo repository.CorporateActionRepository) {
+	ruleGroup := v1.Group("/rules")
+	{
+
+		ruleGroup.POST("/evaluate", handlers.EvaluateRule(repo))
+
+	}
+}
+
+func SizingRoutes(v1 *gin.RouterGroup, repo repository.PositionRepository, repo2 repository.CorporateActionRepository) {
+	sizingGroup := v1.Group("/positions")
+	{
+
+		sizingGroup.POST("/size", handlers.CalculatePositionSize(repo, repo2))
+
+	}
+}
+
+func AlertRoutes(v1 *gin.RouterGroup, repo repository.AlertRepository) {
+	alertGroup := v1.Group("/alerts")
+	{
+		alertGroup.GET("/", handlers.GetAllAlerts(repo))
+		alertGroup.POST("/", handlers.CreateAlert(repo))
+		alertGroup.GET("/events", handlers.GetAlertEvents(repo))
+		alertGroup.DELETE("/:id", handlers.DeleteAlert(repo))
+	}
+}
+
+func AnalyticsRoutes(v1 *gin.RouterGroup, repo repository.AssetRepository, repo2 repository.CorporateActionRepository) {
+	analyticsGroup := v1.Group("/analytics")
+	{
+		analyticsGroup.GET("/correlation", handlers.GetCorrelationMatrix(repo, repo2))
+		analyticsGroup.GET("/:symbol", handlers.GetRiskAnalytics(repo2))
+	}
+}
+
+func OptionRoutes(v1 *gin.RouterGroup, repo repository.CorporateActionRepository) {
+	optionGroup := v1.Group("/options")
+	{
+		optionGroup.GET("/pricing", handlers.GetOptionPricing(repo))
+	}
+}
+
+func BondRoutes(v1 *gin.RouterGroup, repo repository.BondRepository) {
+	bondGroup := v1.Group("/bonds")
+	{
+		bondGroup.GET("/", handlers.GetBonds(repo))
+		bondGroup.POST("/", handlers.CreateBond(repo))
+		bondGroup.GET("/:symbol", handlers.GetBond(repo))
+		bondGroup.GET("/:symbol/analytics", handlers.GetBondAnalytics(repo))
+	}
+}
+
+func PortfolioRoutes(v1 *gin.RouterGroup, repo repository.PositionRepository, repo2 repository.CashRepository, repo3 repository.CorporateActionRepository, reportingCurrency string) {
+	portfolioGroup := v1.Group("/portfolio")
+	{
+		portfolioGroup.GET("/risk", handlers.GetPortfolioRisk(repo, repo3, reportingCurrency))
+		portfolioGroup.GET("/valuation", handlers.GetPortfolioValuation(repo, repo2, reportingCurrency))
+	}
+}
+
+func CorporateActionRoutes(v1 *gin.RouterGroup, repo repository.CorporateActionRepository, uow repository.UnitOfWork, ledger *services.CashLedger) {
+	actionGroup := v1.Group("/corporate-actions")
+	{
+		actionGroup.GET("/", handlers.GetCorporateActions(repo))
+		actionGroup.POST("/import", handlers.ImportCorporateActions(repo, uow, ledger))
+	}
+}
+
+func CashRoutes(v1 *gin.RouterGroup, repo repository.CashRepository, ledger *services.CashLedger) {
+	cashGroup := v1.Group("/cash")
+	{
+		cashGroup.GET("/", handlers.GetCashAccounts(repo))
+		cashGroup.POST("/deposit", handlers.DepositCash(ledger))
+		cashGroup.POST("/withdraw", handlers.WithdrawCash(ledger))
+		cashGroup.PUT("/:currency", handlers.UpdateCashAccount(repo))
+		cashGroup.GET("/:currency/history", handlers.GetCashHistory(repo))
+	}
+}
+
+func AssetRoutes(v1 *gin.RouterGroup, repo repository.AssetRepository, repo2 repository.PositionRepository, repo3 repository.IncomeRepository, repo4 repository.BondRepository, repo5 repository.CorporateActionRepository, uow repository.UnitOfWork, ledger *services.CashLedger) {
 	assetGroup := v1.Group("/assets")
 	{
 		assetGroup.GET("/", handlers.GetAllAssets(repo))
 		assetGroup.POST("/", handlers.CreateAsset(repo))
 		assetGroup.PUT("/:id", handlers.UpdateAsset(repo))
+		assetGroup.GET("/:id/income", handlers.GetAssetIncome(repo, repo2, repo3))
+		assetGroup.POST("/:id/income", handlers.CreateAssetIncome(repo, repo2, repo4, uow, ledger))
 		positionGroup := assetGroup.Group("/:id/positions")
 		{
-			positionGroup.POST("/", handlers.BuyPosition(repo2))
-			positionGroup.PUT("/:idPosition", handlers.SellPosition(repo2))
+			positionGroup.POST("/", handlers.BuyPosition(uow, repo5, ledger))
+			positionGroup.GET("/:idPosition", handlers.GetPosition(repo2))
+			positionGroup.PUT("/:idPosition", handlers.SellPosition(repo2, uow, ledger))
+			positionGroup.PUT("/:idPosition/stop", handlers.SetPositionStop(repo2, repo5))
+			positionGroup.POST("/short", handlers.ShortPosition(uow, ledger))
+			positionGroup.PUT("/:idPosition/cover", handlers.CoverPosition(repo2, uow, ledger))
+			positionGroup.GET("/:idPosition/greeks", handlers.GetPositionGreeks(repo2, repo5))
+			positionGroup.GET("/:idPosition/bond", handlers.GetBondPositionValue(repo2, repo4))
 		}
 	}
 }
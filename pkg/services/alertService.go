@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/megajandrox/go-finance-api/pkg/models"
+	"github.com/megajandrox/go-finance-api/pkg/repository"
+)
+
+// AlertWorker evaluates the active alerts on a schedule
+type AlertWorker struct {
+	repo     repository.AlertRepository
+	actions  repository.CorporateActionRepository
+	interval time.Duration
+	sinks    []AlertSink
+}
+
+func NewAlertWorker(repo repository.AlertRepository, actions repository.CorporateActionRepository, interval time.Duration, sinks ...AlertSink) *AlertWorker {
+	return &AlertWorker{repo: repo, actions: actions, interval: interval, sinks: sinks}
+}
+
+// Start runs the evaluations in background until the context is cancelled
+func (w *AlertWorker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			w.EvaluateAll()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// EvaluateAll evaluates every active alert once and records the triggered events
+func (w *AlertWorker) EvaluateAll() {
+	alerts, err := w.repo.GetActive()
+	if err != nil {
+		log.Printf("Error loading alerts: %v", err)
+		return
+	}
+	cache := newAlertDataCache(NewSplitAdjuster(w.actions))
+	for i := range alerts {
+		w.evaluateAlert(&alerts[i], cache)
+	}
+}
+
+func (w *AlertWorker) evaluateAlert(alert *models.Alert, cache *alertDataCache) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Error evaluating alert %d: %v", alert.ID, r)
+		}
+	}()
+	state, value, message, err := evaluateAlertCondition(alert, cache)
+	if err != nil {
+		log.Printf("Error evaluating alert %d: %v", alert.ID, err)
+		return
+	}
+	now := time.Now()
+	// The first evaluation only sets the baseline, an alert fires on a transition
+	triggered := !alert.LastEvaluatedAt.IsZero() && state && !alert.LastState
+	alert.LastState = state
+	alert.LastEvaluatedAt = now
+	if err := w.repo.Update(alert); err != nil {
+		log.Printf("Error updating alert %d: %v", alert.ID, err)
+		return
+	}
+	if !triggered {
+		return
+	}
+	event := &models.AlertEvent{AlertID: alert.ID, Symbol: alert.Symbol, AlertType: alert.AlertType, Value: value, Message: message, TriggeredAt: now}
+	if err := w.repo.CreateEvent(event); err != nil {
+		log.Printf("Error recording event of alert %d: %v", alert.ID, err)
+		return
+	}
+	w.deliver(event)
+}
+
+func (w *AlertWorker) deliver(event *models.AlertEvent) {
+	delivered := true
+	for _, sink := range w.sinks {
+		if err := sink.Deliver(*event); err != nil {
+			log.Printf("Error delivering event of alert %d: %v", event.AlertID, err)
+			delivered = false
+		}
+	}
+	event.Delivered = delivered
+	if err := w.repo.UpdateEvent(event); err != nil {
+		log.Printf("Error updating event of alert %d: %v", event.AlertID, err)
+	}
+}
+
+// evaluateAlertCondition returns the state of the condition, the observed value and a description
+func evaluateAlertCondition(alert *models.Alert, cache *alertDataCache) (bool, float64, string, error) {
+	switch alert.AlertType {
+	case models.PriceCrossesAbove, models.PriceCrossesBelow:
+		price, err := cache.price(alert.Symbol)
+		if err != nil {
+			return false, 0, "", err
+		}
+		if alert.AlertType == models.PriceCrossesAbove {
+			return price >= alert.Level, price, fmt.Sprintf("Price %.2f crossed above %.2f.", price, alert.Level), nil
+		}
+		return price <= alert.Level, price, fmt.Sprintf("Price %.2f crossed below %.2f.", price, alert.Level), nil
+	case models.RSIEntersOversold, models.RSIEntersOverbought:
+		indexes, err := cache.indexes(alert.Symbol, alert.From, alert.Interval)
+		if err != nil {
+			return false, 0, "", err
+		}
+		if alert.AlertType == models.RSIEntersOversold {
+			return indexes.RSI.TrendType == models.Oversold, indexes.RSI.LatestRSI, indexes.RSI.Result, nil
+		}
+		return indexes.RSI.TrendType == models.Overbought, indexes.RSI.LatestRSI, indexes.RSI.Result, nil
+	case models.MACDBullishCrossover, models.MACDBearishCrossover:
+		indexes, err := cache.indexes(alert.Symbol, alert.From, alert.Interval)
+		if err != nil {
+			return false, 0, "", err
+		}
+		macd := indexes.MACD
+		if len(macd.MACDArray) == 0 || len(macd.MACDSignal) == 0 {
+			return false, 0, "", fmt.Errorf("not enough data for MACD analysis of %s", alert.Symbol)
+		}
+		latestMACD := macd.MACDArray[len(macd.MACDArray)-1]
+		latestSignal := macd.MACDSignal[len(macd.MACDSignal)-1]
+		if alert.AlertType == models.MACDBullishCrossover {
+			return latestMACD > latestSignal, latestMACD, "Bullish crossover detected. MACD has crossed above the Signal line.", nil
+		}
+		return latestMACD < latestSignal, latestMACD, "Bearish crossover detected. MACD has crossed below the Signal line.", nil
+	case models.ExpressionMatches:
+		expression, err := models.ParseExpression(alert.Expression)
+		if err != nil {
+			return false, 0, "", err
+		}
+		marketDataList, err := cache.marketData(alert.Symbol, alert.From, alert.Interval)
+		if err != nil {
+			return false, 0, "", err
+		}
+		value, err := expression.Latest(marketDataList)
+		if err != nil {
+			return false, 0, "", err
+		}
+		return !math.IsNaN(value) && value != 0, value, fmt.Sprintf("Expression %q matched.", alert.Expression), nil
+	default:
+		return false, 0, "", fmt.Errorf("unknown alert type %d", alert.AlertType)
+	}
+}
+
+// alertDataCache avoids fetching the same symbol more than once per evaluation round
+type alertDataCache struct {
+	splits  *SplitAdjuster
+	prices  map[string]float64
+	indexed map[string]models.Indexes
+	bars    map[string][]models.BasicMarketData
+}
+
+func newAlertDataCache(splits *SplitAdjuster) *alertDataCache {
+	return &alertDataCache{splits: splits, prices: map[string]float64{}, indexed: map[string]models.Indexes{}, bars: map[string][]models.BasicMarketData{}}
+}
+
+func (c *alertDataCache) price(symbol string) (float64, error) {
+	if price, ok := c.prices[symbol]; ok {
+		return price, nil
+	}
+	quote, err := FindQuote(symbol)
+	if err != nil {
+		return 0, err
+	}
+	c.prices[symbol] = quote.RegularMarketPrice
+	return quote.RegularMarketPrice, nil
+}
+
+func (c *alertDataCache) indexes(symbol string, from int, interval string) (models.Indexes, error) {
+	key := fmt.Sprintf("%s|%d|%s", symbol, from, interval)
+	if indexes, ok := c.indexed[key]; ok {
+		return indexes, nil
+	}
+	indexes, err := FindIndexesBySymbol(c.splits, symbol, from, interval)
+	if err != nil {
+		return indexes, err
+	}
+	c.indexed[key] = indexes
+	return indexes, nil
+}
+
+func (c *alertDataCache) marketData(symbol string, from int, interval string) ([]models.BasicMarketData, error) {
+	key := fmt.Sprintf("%s|%d|%s", symbol, from, interval)
+	if bars, ok := c.bars[key]; ok {
+		return bars, nil
+	}
+	bars, err := FindMarketDataBySymbol(c.splits, symbol, from, interval)
+	if err != nil {
+		return nil, err
+	}
+	c.bars[key] = bars
+	return bars, nil
+}
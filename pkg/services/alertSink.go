@@ -0,0 +1,51 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/megajandrox/go-finance-api/pkg/models"
+)
+
+// AlertSink delivers a triggered alert event somewhere
+type AlertSink interface {
+	Deliver(event models.AlertEvent) error
+}
+
+// LogSink writes the events to the server log
+type LogSink struct{}
+
+func (s LogSink) Deliver(event models.AlertEvent) error {
+	log.Printf("Alert %d triggered for %s: %s", event.AlertID, event.Symbol, event.Message)
+	return nil
+}
+
+// WebhookSink posts the events as JSON to an URL
+type WebhookSink struct {
+	URL    string
+	client *http.Client
+}
+
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{URL: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *WebhookSink) Deliver(event models.AlertEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding alert event: %w", err)
+	}
+	resp, err := s.client.Post(s.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error posting alert event: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
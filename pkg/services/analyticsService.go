@@ -0,0 +1,41 @@
+package services
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/megajandrox/go-finance-api/pkg/models"
+)
+
+// FindRiskAnalyticsBySymbol calculates the volatility of the symbol and its beta and correlation with the benchmark
+func FindRiskAnalyticsBySymbol(splits *SplitAdjuster, symbol string, benchmark string, from int, interval string) (*models.RiskAnalytics, error) {
+	marketDataList, err := FindMarketDataBySymbol(splits, symbol, from, interval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the bars of %s: %w", symbol, err)
+	}
+	var benchmarkData []models.BasicMarketData
+	if benchmark != "" {
+		benchmarkData, err = FindMarketDataBySymbol(splits, benchmark, from, interval)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve the bars of %s: %w", benchmark, err)
+		}
+	}
+	return models.CalculateRiskAnalytics(symbol, marketDataList, benchmark, benchmarkData, interval)
+}
+
+// FindCorrelationMatrix correlates the returns of the symbols, the ones without bars are left out
+func FindCorrelationMatrix(splits *SplitAdjuster, symbols []string, from int, interval string) models.CorrelationMatrix {
+	marketData := map[string][]models.BasicMarketData{}
+	for _, symbol := range symbols {
+		if _, ok := marketData[symbol]; ok {
+			continue
+		}
+		marketDataList, err := FindMarketDataBySymbol(splits, symbol, from, interval)
+		if err != nil || len(marketDataList) == 0 {
+			log.Printf("Skipping %s from the correlation matrix: %v", symbol, err)
+			continue
+		}
+		marketData[symbol] = marketDataList
+	}
+	return models.CalculateCorrelationMatrix(marketData, models.IsDailyInterval(interval))
+}
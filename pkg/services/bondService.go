@@ -0,0 +1,29 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/megajandrox/go-finance-api/pkg/models"
+)
+
+// FindBondAnalytics analyzes the bond at the clean price, or at its quote when the price is not given
+func FindBondAnalytics(bond *models.BondIssue, price float64, settlement time.Time) (*models.BondAnalytics, error) {
+	if price <= 0 {
+		quote, err := FindQuote(bond.Symbol)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve the quote of %s: %w", bond.Symbol, err)
+		}
+		price = quote.RegularMarketPrice
+	}
+	return bond.Analyze(price, settlement)
+}
+
+// FindBondPositionValue values the position at the quote of the bond plus its accrued interest
+func FindBondPositionValue(bond *models.BondIssue, position *models.Position, settlement time.Time) (*models.BondPositionValue, error) {
+	analytics, err := FindBondAnalytics(bond, 0, settlement)
+	if err != nil {
+		return nil, err
+	}
+	return analytics.ValuePosition(position), nil
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/megajandrox/go-finance-api/pkg/calendar"
+	"github.com/megajandrox/go-finance-api/pkg/models"
+	"github.com/megajandrox/go-finance-api/pkg/repository"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// CashLedger moves the cash of the accounts, the account of a currency is created on its first movement
+type CashLedger struct {
+	repo           repository.CashRepository
+	allowOverdraft bool // Overdraft of the accounts it creates
+}
+
+func NewCashLedger(repo repository.CashRepository, allowOverdraft bool) *CashLedger {
+	return &CashLedger{repo: repo, allowOverdraft: allowOverdraft}
+}
+
+// WithRepository returns the same ledger writing through repo, used to move the cash inside a transaction
+func (l *CashLedger) WithRepository(repo repository.CashRepository) *CashLedger {
+	return &CashLedger{repo: repo, allowOverdraft: l.allowOverdraft}
+}
+
+// CurrencyOf returns the currency of the position when it was not recorded: the quote currency of a crypto
+// or FX pair, the one of its exchange otherwise
+func CurrencyOf(position *models.Position) string {
+	if position.Currency != "" {
+		return position.Currency
+	}
+	if position.IsCurrencyPair() {
+		if pair, ok := models.ParseCurrencyPair(position.Symbol); ok {
+			return pair.Quote
+		}
+	}
+	return calendar.ForSymbol(position.Symbol).Currency
+}
+
+// Account returns the account of the currency, creating it when it does not exist
+func (l *CashLedger) Account(currency string) (*models.CashAccount, error) {
+	currency = strings.ToUpper(currency)
+	account, err := l.repo.GetByCurrency(currency)
+	if err == nil {
+		return account, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	account, err = models.NewCashAccount(currency, l.allowOverdraft)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.repo.Create(account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// CheckFunds fails with models.ErrInsufficientCash when the account cannot pay the amount
+func (l *CashLedger) CheckFunds(currency string, transactionType models.CashTransactionType, amount float64) error {
+	account, err := l.Account(currency)
+	if err != nil {
+		return err
+	}
+	if !account.CanDebit(transactionType, amount) {
+		return fmt.Errorf("%w: %.2f %s available, %.2f needed", models.ErrInsufficientCash, account.Balance, account.Currency, amount)
+	}
+	return nil
+}
+
+// Record applies the movement to the account of the currency and stores it. The account is locked while
+// the balance is updated, inside the transaction of the ledger when it writes through one.
+func (l *CashLedger) Record(currency string, transactionType models.CashTransactionType, amount float64, description string, positionID *uint, incomeID *uint) (*models.CashTransaction, error) {
+	var transaction *models.CashTransaction
+	err := l.repo.Transaction(func(repo repository.CashRepository) error {
+		ledger := l.WithRepository(repo)
+		account, err := ledger.Account(currency)
+		if err != nil {
+			return err
+		}
+		if account, err = repo.GetByCurrencyForUpdate(account.Currency); err != nil {
+			return err
+		}
+		if transaction, err = account.Apply(transactionType, amount, description); err != nil {
+			return err
+		}
+		transaction.PositionID = positionID
+		transaction.IncomeID = incomeID
+		if err := repo.Update(account); err != nil {
+			return err
+		}
+		return repo.CreateTransaction(transaction)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// RecordBuy debits the cost and the fee of a new position
+func (l *CashLedger) RecordBuy(position *models.Position, fee float64) error {
+	cost := position.Notional(position.EntryPrice, position.Quantity)
+	if _, err := l.Record(CurrencyOf(position), models.BuyDebit, cost, fmt.Sprintf("Buy %s %s", position.Quantity, position.Symbol), &position.ID, nil); err != nil {
+		return err
+	}
+	return l.recordFee(position, fee)
+}
+
+// RecordSell credits the proceeds of a sale and debits its fee
+func (l *CashLedger) RecordSell(position *models.Position, price float64, quantity decimal.Decimal, fee float64) error {
+	if proceeds := position.Notional(price, quantity); proceeds > 0 {
+		if _, err := l.Record(CurrencyOf(position), models.SellCredit, proceeds, fmt.Sprintf("Sell %s %s", quantity, position.Symbol), &position.ID, nil); err != nil {
+			return err
+		}
+	}
+	return l.recordFee(position, fee)
+}
+
+// RecordShort credits the proceeds of a short sale and debits its fee
+func (l *CashLedger) RecordShort(position *models.Position, fee float64) error {
+	proceeds := position.Notional(position.EntryPrice, position.Quantity)
+	if _, err := l.Record(CurrencyOf(position), models.SellCredit, proceeds, fmt.Sprintf("Short %s %s", position.Quantity, position.Symbol), &position.ID, nil); err != nil {
+		return err
+	}
+	return l.recordFee(position, fee)
+}
+
+// RecordCover debits the shares bought to cover a short, its fee and the borrow fees of the covered shares
+func (l *CashLedger) RecordCover(position *models.Position, price float64, quantity decimal.Decimal, fee float64, borrowFees float64) error {
+	if cost := position.Notional(price, quantity); cost > 0 {
+		if _, err := l.Record(CurrencyOf(position), models.BuyDebit, cost, fmt.Sprintf("Cover %s %s", quantity, position.Symbol), &position.ID, nil); err != nil {
+			return err
+		}
+	}
+	if borrowFees > 0 {
+		if _, err := l.Record(CurrencyOf(position), models.FeeDebit, borrowFees, fmt.Sprintf("Borrow fee %s", position.Symbol), &position.ID, nil); err != nil {
+			return err
+		}
+	}
+	return l.recordFee(position, fee)
+}
+
+// RecordCashInLieu credits the fraction of share a split pays in cash, or debits it from a short that owes it
+func (l *CashLedger) RecordCashInLieu(position *models.Position, price float64, quantity decimal.Decimal) error {
+	amount := position.Notional(price, quantity)
+	if amount <= 0 {
+		return nil
+	}
+	transactionType := models.SellCredit
+	if position.IsShort() {
+		transactionType = models.BuyDebit
+	}
+	_, err := l.Record(CurrencyOf(position), transactionType, amount, fmt.Sprintf("Cash in lieu of %s %s", quantity, position.Symbol), &position.ID, nil)
+	return err
+}
+
+func (l *CashLedger) recordFee(position *models.Position, fee float64) error {
+	if fee <= 0 {
+		return nil
+	}
+	_, err := l.Record(CurrencyOf(position), models.FeeDebit, fee, fmt.Sprintf("Fee %s", position.Symbol), &position.ID, nil)
+	return err
+}
+
+// IncomeCurrency returns the currency the income of the Asset is paid in: the one of its bond issue,
+// else the one of its positions, else the one of its exchange
+func IncomeCurrency(bonds repository.BondRepository, positions repository.PositionRepository, assetID uint, symbol string) (string, error) {
+	bond, err := bonds.GetBySymbol(symbol)
+	if err == nil && bond.Currency != "" {
+		return bond.Currency, nil
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	held, err := positions.GetByAsset(assetID)
+	if err != nil {
+		return "", err
+	}
+	if len(held) > 0 {
+		return CurrencyOf(&held[0]), nil
+	}
+	return calendar.ForSymbol(symbol).Currency, nil
+}
+
+// RecordIncome credits a dividend, coupon or interest payment to the account of the currency
+func (l *CashLedger) RecordIncome(income *models.Income, currency string) error {
+	_, err := l.Record(currency, models.IncomeCredit, income.Amount, fmt.Sprintf("%s %s", income.IncomeType, income.Symbol), nil, &income.ID)
+	return err
+}
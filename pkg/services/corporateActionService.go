@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/megajandrox/go-finance-api/pkg/models"
+	"github.com/megajandrox/go-finance-api/pkg/repository"
+	"github.com/shopspring/decimal"
+)
+
+// SplitAdjuster adjusts the bars of the provider for the splits recorded in the repository. The splits of a
+// symbol are read once, so an adjuster is created for every request or evaluation round and is not shared
+// between goroutines. A nil adjuster leaves the bars as they are.
+type SplitAdjuster struct {
+	repo   repository.CorporateActionRepository
+	splits map[string][]models.CorporateAction
+}
+
+func NewSplitAdjuster(repo repository.CorporateActionRepository) *SplitAdjuster {
+	return &SplitAdjuster{repo: repo, splits: map[string][]models.CorporateAction{}}
+}
+
+// Adjust applies the recorded splits of the symbol to its bars
+func (a *SplitAdjuster) Adjust(symbol string, marketDataList []models.BasicMarketData) []models.BasicMarketData {
+	if a == nil || a.repo == nil || len(marketDataList) == 0 {
+		return marketDataList
+	}
+	actions, ok := a.splits[symbol]
+	if !ok {
+		var err error
+		actions, err = a.repo.GetBySymbol(symbol)
+		if err != nil {
+			log.Printf("Error loading corporate actions of %s, the bars are not adjusted: %v", symbol, err)
+			return marketDataList
+		}
+		a.splits[symbol] = actions
+	}
+	return models.AdjustBarsForSplits(marketDataList, actions)
+}
+
+// ImportCorporateActions stores the actions that are not recorded yet, the same symbol, type and ex-date is skipped
+func ImportCorporateActions(repo repository.CorporateActionRepository, actions []*models.CorporateAction) ([]*models.CorporateAction, int, error) {
+	var created []*models.CorporateAction
+	skipped := 0
+	existing := map[string][]models.CorporateAction{}
+	for _, action := range actions {
+		if _, ok := existing[action.Symbol]; !ok {
+			stored, err := repo.GetBySymbol(action.Symbol)
+			if err != nil {
+				return created, skipped, err
+			}
+			existing[action.Symbol] = stored
+		}
+		duplicated := false
+		for _, stored := range existing[action.Symbol] {
+			if stored.ActionType == action.ActionType && stored.ExDate.Equal(action.ExDate) {
+				duplicated = true
+				break
+			}
+		}
+		if duplicated {
+			skipped++
+			continue
+		}
+		if err := repo.Create(action); err != nil {
+			return created, skipped, err
+		}
+		existing[action.Symbol] = append(existing[action.Symbol], *action)
+		created = append(created, action)
+	}
+	return created, skipped, nil
+}
+
+// ApplyPendingCorporateActions applies the actions whose ex-date was reached to the positions held before it:
+// splits change the quantity and cost per share, cash dividends are recorded as income of the Asset
+// and charged to the shorts. Every action is claimed and applied in one transaction, a failed action is
+// rolled back whole and retried on the next run, and an action claimed by a concurrent run is skipped.
+func ApplyPendingCorporateActions(uow repository.UnitOfWork, actions repository.CorporateActionRepository, ledger *CashLedger) (int, error) {
+	pending, err := actions.GetPending(time.Now())
+	if err != nil {
+		return 0, err
+	}
+	applied := 0
+	for i := range pending {
+		action := &pending[i]
+		inLieuPrice, err := cashInLieuPrice(action)
+		if err != nil {
+			log.Printf("Error pricing the cash in lieu of %s %d of %s, it stays pending: %v", action.ActionType, action.ID, action.Symbol, err)
+			continue
+		}
+		claimed := false
+		err = uow.Transaction(func(repos repository.Repositories) error {
+			action.AppliedAt = time.Now()
+			ok, err := repos.CorporateActions.Claim(action.ID, action.AppliedAt)
+			if err != nil || !ok {
+				return err
+			}
+			allPositions, err := repos.Positions.GetAll()
+			if err != nil {
+				return err
+			}
+			if err := applyCorporateAction(action, allPositions, inLieuPrice, repos, ledger.WithRepository(repos.Cash)); err != nil {
+				return err
+			}
+			claimed = true
+			return nil
+		})
+		if err != nil {
+			log.Printf("Error applying %s %d of %s, it stays pending: %v", action.ActionType, action.ID, action.Symbol, err)
+			continue
+		}
+		if claimed {
+			action.Applied = true
+			applied++
+			log.Printf("%s of %s on %s applied", action.ActionType, action.Symbol, action.ExDate.Format("2006-01-02"))
+		}
+	}
+	return applied, nil
+}
+
+// cashInLieuPrice returns the price the fractions of share of a split are paid at: the one of the action,
+// else the quote of the symbol. It is read before the transaction, so the positions are not locked while
+// the provider answers.
+func cashInLieuPrice(action *models.CorporateAction) (float64, error) {
+	if action.ActionType != models.Split || action.CashInLieuPrice > 0 {
+		return action.CashInLieuPrice, nil
+	}
+	quote, err := FindQuote(action.Symbol)
+	if err != nil {
+		return 0, err
+	}
+	return quote.RegularMarketPrice, nil
+}
+
+// applyCorporateAction updates the positions held at the ex-date through repos, the first failure stops it
+// so the caller rolls the whole action back. The fractions of share a split leaves are paid at inLieuPrice.
+func applyCorporateAction(action *models.CorporateAction, allPositions []models.Position, inLieuPrice float64, repos repository.Repositories, ledger *CashLedger) error {
+	heldByAsset := map[uint]decimal.Decimal{}
+	currencyByAsset := map[uint]string{}
+	for i := range allPositions {
+		position := &allPositions[i]
+		if position.Symbol != action.Symbol || !position.IsHeldAt(action.ExDate) {
+			continue
+		}
+		switch action.ActionType {
+		case models.Split:
+			// The position is read again locked, so a trade committed since GetAll is not reverted
+			position, err := repos.Positions.GetByIDForUpdate(position.ID)
+			if err != nil {
+				return fmt.Errorf("position %d: %w", allPositions[i].ID, err)
+			}
+			if !position.IsHeldAt(action.ExDate) {
+				continue
+			}
+			inLieu, err := position.ApplySplit(action.Ratio, inLieuPrice)
+			if err != nil {
+				return fmt.Errorf("position %d: %w", position.ID, err)
+			}
+			if err := repos.Positions.Update(position); err != nil {
+				return fmt.Errorf("position %d: %w", position.ID, err)
+			}
+			if err := ledger.RecordCashInLieu(position, inLieuPrice, inLieu); err != nil {
+				return fmt.Errorf("position %d: %w", position.ID, err)
+			}
+		case models.CashDividend:
+			if position.IsShort() {
+				// The short pays the dividend of the borrowed shares to the lender
+				owed := action.Amount * models.QuantityFloat(position.Quantity)
+				description := fmt.Sprintf("Dividend owed on short %s", position.Symbol)
+				if _, err := ledger.Record(CurrencyOf(position), models.FeeDebit, owed, description, &position.ID, nil); err != nil {
+					return fmt.Errorf("short position %d: %w", position.ID, err)
+				}
+				continue
+			}
+			heldByAsset[position.AssetID] = heldByAsset[position.AssetID].Add(position.Quantity)
+			currencyByAsset[position.AssetID] = CurrencyOf(position)
+		}
+	}
+	for assetID, quantity := range heldByAsset {
+		income, err := models.NewDividendIncome(assetID, action, quantity)
+		if err == nil {
+			err = repos.Incomes.Create(income)
+		}
+		if err == nil {
+			err = ledger.RecordIncome(income, currencyByAsset[assetID])
+		}
+		if err != nil {
+			return fmt.Errorf("dividend of asset %d: %w", assetID, err)
+		}
+	}
+	return nil
+}
+
+// CorporateActionWorker applies the corporate actions when their ex-date is reached
+type CorporateActionWorker struct {
+	uow      repository.UnitOfWork
+	actions  repository.CorporateActionRepository
+	ledger   *CashLedger
+	interval time.Duration
+}
+
+func NewCorporateActionWorker(uow repository.UnitOfWork, actions repository.CorporateActionRepository, ledger *CashLedger, interval time.Duration) *CorporateActionWorker {
+	return &CorporateActionWorker{uow: uow, actions: actions, ledger: ledger, interval: interval}
+}
+
+// Start runs the worker in background until the context is cancelled
+func (w *CorporateActionWorker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			if _, err := ApplyPendingCorporateActions(w.uow, w.actions, w.ledger); err != nil {
+				log.Printf("Error applying corporate actions: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
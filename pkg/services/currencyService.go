@@ -0,0 +1,50 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CurrencyConverter converts amounts at the latest quotes of the currency pairs, every pair is quoted once
+type CurrencyConverter struct {
+	rates map[string]float64
+}
+
+func NewCurrencyConverter() *CurrencyConverter {
+	return &CurrencyConverter{rates: map[string]float64{}}
+}
+
+// Rate returns the units of 'to' paid for one unit of 'from', from the FX pair (EURUSD=X) or the crypto
+// pair (BTC-USD) in either direction
+func (c *CurrencyConverter) Rate(from string, to string) (float64, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return 1, nil
+	}
+	key := from + "/" + to
+	if rate, ok := c.rates[key]; ok {
+		return rate, nil
+	}
+	for _, symbol := range []string{from + to + "=X", from + "-" + to} {
+		if quote, err := FindQuote(symbol); err == nil && quote.RegularMarketPrice > 0 {
+			c.rates[key] = quote.RegularMarketPrice
+			return c.rates[key], nil
+		}
+	}
+	for _, symbol := range []string{to + from + "=X", to + "-" + from} {
+		if quote, err := FindQuote(symbol); err == nil && quote.RegularMarketPrice > 0 {
+			c.rates[key] = 1 / quote.RegularMarketPrice
+			return c.rates[key], nil
+		}
+	}
+	return 0, fmt.Errorf("no quote converts %s into %s", from, to)
+}
+
+// Convert returns the amount of 'from' in units of 'to'
+func (c *CurrencyConverter) Convert(amount float64, from string, to string) (float64, error) {
+	rate, err := c.Rate(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return amount * rate, nil
+}
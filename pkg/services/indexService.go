@@ -1,74 +1,204 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
+	"github.com/megajandrox/go-finance-api/pkg/calendar"
 	"github.com/megajandrox/go-finance-api/pkg/models"
 	"github.com/piquette/finance-go/chart"
 	"github.com/piquette/finance-go/datetime"
 )
 
-func ConvertTimestamp(timestamp int64) string {
-	t := time.Unix(timestamp, 0)
-	return t.Format("2006-01-02 15:04")
+// ConvertTimestamp labels the timestamp in the time zone of the exchange of the symbol
+func ConvertTimestamp(symbol string, timestamp int64) string {
+	return calendar.ForSymbol(symbol).FormatTimestamp(timestamp)
 }
 
-// getQuote handles the retrieval of stock quotes
-func FindIndexesBySymbol(symbol string, from int, interval string) (models.Indexes, error) {
-	startDateTime := &datetime.Datetime{
-		Month: int(time.Now().AddDate(0, from*-1, 0).Month()),
-		Day:   1,
-		Year:  time.Now().AddDate(0, from*-1, 0).Year(),
+// MonthRange returns the window that starts on day 1, 'from' months before 'end'
+func MonthRange(from int, end time.Time) (time.Time, time.Time) {
+	month := end.AddDate(0, from*-1, 0)
+	return time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, end.Location()), end
+}
+
+// lookbackRange is the MonthRange that ends now, cut to the lookback of the interval
+func lookbackRange(from int, interval string) (time.Time, time.Time) {
+	start, end := MonthRange(from, time.Now())
+	if lookback := models.MaxLookback(interval); lookback > 0 && start.Before(end.Add(-lookback)) {
+		start = end.Add(-lookback)
 	}
-	endDateTime := &datetime.Datetime{
-		Month: int(time.Now().Month()),
-		Day:   time.Now().Day(),
-		Year:  time.Now().Year(),
+	return start, end
+}
+
+// FindMarketDataBySymbol retrieves the bars of the symbol for the last 'from' months,
+// or as far back as the interval is available
+func FindMarketDataBySymbol(splits *SplitAdjuster, symbol string, from int, interval string) ([]models.BasicMarketData, error) {
+	start, end := lookbackRange(from, interval)
+	return FindMarketDataBetween(splits, symbol, start, end, interval)
+}
+
+// resampledIntervals are built from the bars of a finer interval, in the time zone of the exchange,
+// because the provider labels the weekly and monthly bars inconsistently
+var resampledIntervals = map[string]string{
+	"1wk": "1d",
+	"1mo": "1d",
+}
+
+// FindMarketDataBetween retrieves the bars of the symbol that open between start and end, both included,
+// adjusted for the splits of the adjuster
+func FindMarketDataBetween(splits *SplitAdjuster, symbol string, start time.Time, end time.Time, interval string) ([]models.BasicMarketData, error) {
+	sourceInterval := interval
+	if base, ok := resampledIntervals[interval]; ok {
+		sourceInterval = base
 	}
 	params := &chart.Params{
 		Symbol:   symbol,
-		Interval: datetime.Interval(interval),
-		Start:    startDateTime,
-		End:      endDateTime,
+		Interval: datetime.Interval(sourceInterval),
+		Start:    datetime.New(&start),
+		End:      datetime.New(&end),
 	}
 	iter := chart.Get(params)
-	indexesResult := models.NewIndexes(symbol)
 	var marketDataList []models.BasicMarketData
 	for iter.Next() {
 		p := iter.Bar()
+		if int64(p.Timestamp) < start.Unix() || int64(p.Timestamp) > end.Unix() {
+			continue
+		}
+		open, _ := p.Open.Float64()
 		close, _ := p.Close.Float64()
 		high, _ := p.High.Float64()
 		low, _ := p.Low.Float64()
-		var marketData = models.BasicMarketData{Close: close, High: high, Low: low, Volume: int64(p.Volume), TimeStamp: int64(p.Timestamp)}
+		var marketData = models.BasicMarketData{Open: open, Close: close, High: high, Low: low, Volume: int64(p.Volume), TimeStamp: int64(p.Timestamp)}
 		marketDataList = append(marketDataList, marketData)
 	}
 	if err := iter.Err(); err != nil {
-		fmt.Println(err)
+		return marketDataList, err
+	}
+	marketDataList = splits.Adjust(symbol, marketDataList)
+	if sourceInterval == interval || len(marketDataList) == 0 {
+		return marketDataList, nil
+	}
+	return models.Resample(marketDataList, interval, calendar.ForSymbol(symbol))
+}
+
+// ErrUnknownAnalyzer is returned when a selected analyzer name is not one of AnalyzerNames
+var ErrUnknownAnalyzer = errors.New("unknown analyzer")
+
+// analyzerAdapters maps the names accepted by the index analysis to the analyzers, in execution order
+var analyzerAdapters = []struct {
+	name    string
+	adapter func(string) (models.Analyzer, error)
+}{
+	{"sma", models.NewSMAAdapter},
+	{"ema", models.NewEMAAdapter},
+	{"macd", models.NewMACDAdapter},
+	{"rsi", models.NewRSIAdapter},
+	{"stochastic", models.NewStochasticAdapter},
+	{"volume", models.NewVolumeAdapter},
+	{"obv", models.NewOBVAdapter},
+	{"rvol", models.NewRVOLAdapter},
+	{"atr", models.NewATRAdapter},
+	{"adx", models.NewADXAdapter},
+	{"momentum", models.NewMomentumAdapter},
+	{"cci", models.NewCCIAdapter},
+	{"support_resistance", models.NewSupportResistanceAdapter},
+	{"fibonacci", models.NewFibonacciAdapter},
+	{"divergence", models.NewDivergenceAdapter},
+	{"candlestick", models.NewCandlestickAdapter},
+	{"ichimoku", models.NewIchimokuAdapter},
+	{"vwap", models.NewVWAPAdapter},
+	{"mfi", models.NewMFIAdapter},
+	{"williams_r", models.NewWilliamsRAdapter},
+	{"cmf", models.NewCMFAdapter},
+	{"supertrend", models.NewSupertrendAdapter},
+	{"aroon", models.NewAroonAdapter},
+	{"trix", models.NewTRIXAdapter},
+}
+
+// AnalyzerNames returns the names of the analyzers that can be selected
+func AnalyzerNames() []string {
+	names := make([]string, len(analyzerAdapters))
+	for i, a := range analyzerAdapters {
+		names[i] = a.name
+	}
+	return names
+}
+
+// getQuote handles the retrieval of stock quotes
+func FindIndexesBySymbol(splits *SplitAdjuster, symbol string, from int, interval string) (models.Indexes, error) {
+	return FindSelectedIndexesBySymbol(splits, symbol, from, interval, nil)
+}
+
+// FindSelectedIndexesBySymbol runs only the named analyzers, every analyzer when names is empty
+func FindSelectedIndexesBySymbol(splits *SplitAdjuster, symbol string, from int, interval string, names []string) (models.Indexes, error) {
+	start, end := lookbackRange(from, interval)
+	return FindSelectedIndexesBetween(splits, symbol, start, end, interval, names)
+}
+
+// FindSelectedIndexesBetween runs the named analyzers on the bars between start and end,
+// so a past end shows what the indicators said at that time
+func FindSelectedIndexesBetween(splits *SplitAdjuster, symbol string, start time.Time, end time.Time, interval string, names []string) (models.Indexes, error) {
+	selected := map[string]bool{}
+	for _, name := range names {
+		selected[name] = true
+	}
+	var analyzers []func(string) (models.Analyzer, error)
+	for _, a := range analyzerAdapters {
+		if len(names) == 0 || selected[a.name] {
+			analyzers = append(analyzers, a.adapter)
+			delete(selected, a.name)
+		}
+	}
+	for name := range selected {
+		return models.Indexes{}, fmt.Errorf("%w %q", ErrUnknownAnalyzer, name)
 	}
 
-	analyzers := []func(string) (models.Analyzer, error){
-		models.NewSMAAdapter,
-		models.NewEMAAdapter,
-		models.NewMACDAdapter,
-		models.NewRSIAdapter,
-		models.NewStochasticAdapter,
-		models.NewVolumeAdapter,
-		models.NewOBVAdapter,
-		models.NewRVOLAdapter,
-		models.NewATRAdapter,
-		models.NewADXAdapter,
-		models.NewMomentumAdapter,
-		models.NewCCIAdapter,
+	indexesResult := models.NewIndexes(symbol)
+	marketDataList, err := FindMarketDataBetween(splits, symbol, start, end, interval)
+	if err != nil {
+		return models.Indexes{}, err
 	}
 
 	for _, newAnalyzer := range analyzers {
-		var err error
-		indexesResult, err = models.RunAnalysis(symbol, marketDataList, indexesResult, newAnalyzer)
+		result, err := models.RunAnalysis(symbol, marketDataList, indexesResult, newAnalyzer)
 		if err != nil {
-			log.Println("Error running analysis: %v", err)
+			log.Printf("Error running analysis: %v", err)
+		}
+		if result != nil {
+			indexesResult = result
 		}
 	}
+
+	// The gaps are only checked for sessions and intraday bars, when the provider answered
+	if step, ok := models.IntervalDuration(interval); len(marketDataList) > 0 && (ok || interval == "1d") {
+		timestamps := make([]int64, len(marketDataList))
+		for i, d := range marketDataList {
+			timestamps[i] = d.TimeStamp
+		}
+		indexesResult.MissingBars = calendar.ForSymbol(symbol).MissingBars(timestamps, step, start, end)
+	}
 	return *indexesResult, nil
 }
+
+// FindVWAPBySymbol calculates the session VWAP and, when the anchor is not zero, the anchored VWAP
+func FindVWAPBySymbol(splits *SplitAdjuster, symbol string, from int, interval string, anchor time.Time) (*models.VWAP, error) {
+	marketDataList, err := FindMarketDataBySymbol(splits, symbol, from, interval)
+	if err != nil {
+		return nil, err
+	}
+	var vwap *models.VWAP
+	if anchor.IsZero() {
+		vwap, err = models.NewVWAP(symbol)
+	} else {
+		vwap, err = models.NewAnchoredVWAP(symbol, anchor)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := vwap.Analyze(marketDataList); err != nil {
+		return nil, err
+	}
+	return vwap, nil
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/megajandrox/go-finance-api/pkg/calendar"
+	"github.com/megajandrox/go-finance-api/pkg/models"
+)
+
+// FindOptionValuation prices the contract from the quote and the last year of daily volatility of the underlying.
+// rate is the annual risk-free rate as a decimal, a positive marketPrice also solves the implied volatility.
+func FindOptionValuation(splits *SplitAdjuster, contract models.OptionContract, rate float64, marketPrice float64) (*models.OptionValuation, error) {
+	quote, err := FindQuote(contract.Underlying)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the quote of %s: %w", contract.Underlying, err)
+	}
+	marketDataList, err := FindMarketDataBySymbol(splits, contract.Underlying, 12, "1d")
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the bars of %s: %w", contract.Underlying, err)
+	}
+	volatility, err := models.CloseToCloseVolatility(marketDataList, models.PeriodsPerYear("1d", calendar.ForSymbol(contract.Underlying)))
+	if err != nil {
+		return nil, err
+	}
+	return models.ValueOption(contract, quote.RegularMarketPrice, volatility, rate, marketPrice, time.Now())
+}
+
+// FindPositionValuation prices the option of the position at the quote of the contract and scales the greeks
+// by its contracts
+func FindPositionValuation(splits *SplitAdjuster, position *models.Position, rate float64) (*models.OptionValuation, error) {
+	if !position.IsOption() {
+		return nil, errors.New("the position is not on an option")
+	}
+	marketPrice := 0.0
+	if quote, err := FindQuote(position.Symbol); err == nil {
+		marketPrice = quote.RegularMarketPrice
+	} else {
+		log.Printf("Pricing position %d without the quote of %s: %v", position.ID, position.Symbol, err)
+	}
+	valuation, err := FindOptionValuation(splits, position.Option, rate, marketPrice)
+	if err != nil {
+		return nil, err
+	}
+	valuation.ForPosition(position)
+	return valuation, nil
+}
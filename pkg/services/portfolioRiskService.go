@@ -0,0 +1,121 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/megajandrox/go-finance-api/pkg/models"
+)
+
+// symbolCurrencies returns the currency of the prices of every symbol with positions
+func symbolCurrencies(positions []models.Position) map[string]string {
+	currencies := map[string]string{}
+	for i := range positions {
+		if _, ok := currencies[positions[i].Symbol]; !ok {
+			currencies[positions[i].Symbol] = CurrencyOf(&positions[i])
+		}
+	}
+	return currencies
+}
+
+// convertBars returns a copy of the bars with the prices multiplied by the rate
+func convertBars(marketDataList []models.BasicMarketData, rate float64) []models.BasicMarketData {
+	converted := make([]models.BasicMarketData, len(marketDataList))
+	for i, d := range marketDataList {
+		d.Open *= rate
+		d.High *= rate
+		d.Low *= rate
+		d.Close *= rate
+		converted[i] = d
+	}
+	return converted
+}
+
+// FindPortfolioRisk calculates the VaR and CVaR of the open positions from the daily bars of the last 'from' months.
+// The bars are converted into the reporting currency at the latest rate, the risk of the rates is not included.
+func FindPortfolioRisk(splits *SplitAdjuster, positions []models.Position, confidence float64, from int, currency string) (*models.PortfolioRisk, error) {
+	holdings := models.OpenHoldings(positions)
+	currencies := symbolCurrencies(positions)
+	converter := NewCurrencyConverter()
+	marketData := map[string][]models.BasicMarketData{}
+	for symbol := range holdings {
+		marketDataList, err := FindMarketDataBySymbol(splits, symbol, from, "1d")
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve the bars of %s: %w", symbol, err)
+		}
+		rate, err := converter.Rate(currencies[symbol], currency)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s: %w", symbol, err)
+		}
+		marketData[symbol] = convertBars(marketDataList, rate)
+	}
+	risk, err := models.CalculatePortfolioRisk(holdings, marketData, confidence)
+	if err != nil {
+		return nil, err
+	}
+	risk.Currency = currency
+	return risk, nil
+}
+
+// FindPortfolioValuation values the open positions at their quotes and the cash balances in the reporting currency.
+// What cannot be priced or converted is reported and left out of the totals.
+func FindPortfolioValuation(positions []models.Position, accounts []models.CashAccount, currency string) *models.PortfolioValuation {
+	valuation := &models.PortfolioValuation{Currency: currency, Holdings: []models.HoldingValuation{}, Cash: []models.CashValuation{}}
+	converter := NewCurrencyConverter()
+
+	bySymbol := map[string][]*models.Position{}
+	for i := range positions {
+		if positions[i].IsOpen() {
+			bySymbol[positions[i].Symbol] = append(bySymbol[positions[i].Symbol], &positions[i])
+		}
+	}
+	symbols := make([]string, 0, len(bySymbol))
+	for symbol := range bySymbol {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	for _, symbol := range symbols {
+		first := bySymbol[symbol][0]
+		holding := models.HoldingValuation{Symbol: symbol, MarketType: first.MarketType.String(), Currency: CurrencyOf(first)}
+		// Longs and shorts of the symbol are netted in Quantity, a long hedged by a short still has exposure
+		for _, p := range bySymbol[symbol] {
+			if p.IsShort() {
+				holding.ShortQuantity = holding.ShortQuantity.Add(p.Quantity)
+			} else {
+				holding.LongQuantity = holding.LongQuantity.Add(p.Quantity)
+			}
+		}
+		holding.Quantity = holding.LongQuantity.Sub(holding.ShortQuantity)
+		quote, err := FindQuote(symbol)
+		if err != nil {
+			log.Printf("Leaving %s out of the valuation: %v", symbol, err)
+			valuation.Unpriced = append(valuation.Unpriced, symbol)
+			continue
+		}
+		holding.Price = quote.RegularMarketPrice
+		holding.MarketValue = first.Notional(holding.Price, holding.Quantity)
+		rate, err := converter.Rate(holding.Currency, currency)
+		if err != nil {
+			log.Printf("Leaving %s out of the valuation: %v", symbol, err)
+			valuation.Unpriced = append(valuation.Unpriced, symbol)
+			continue
+		}
+		valuation.AddHolding(holding, first.Notional(holding.Price, holding.LongQuantity), first.Notional(holding.Price, holding.ShortQuantity), rate)
+	}
+
+	for _, account := range accounts {
+		if account.Balance == 0 {
+			continue
+		}
+		rate, err := converter.Rate(account.Currency, currency)
+		if err != nil {
+			log.Printf("Leaving the %s cash out of the valuation: %v", account.Currency, err)
+			valuation.Unpriced = append(valuation.Unpriced, account.Currency)
+			continue
+		}
+		valuation.AddCash(account.Currency, account.Balance, rate)
+	}
+	return valuation
+}
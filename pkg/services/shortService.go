@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/megajandrox/go-finance-api/pkg/repository"
+)
+
+// BorrowFeeWorker accrues the borrow fees of the open shorts at the latest quotes
+type BorrowFeeWorker struct {
+	repo     repository.PositionRepository
+	interval time.Duration
+}
+
+func NewBorrowFeeWorker(repo repository.PositionRepository, interval time.Duration) *BorrowFeeWorker {
+	return &BorrowFeeWorker{repo: repo, interval: interval}
+}
+
+// Start runs the accruals in background until the context is cancelled
+func (w *BorrowFeeWorker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			w.AccrueAll()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// AccrueAll adds the fees since the last accrual to every open short
+func (w *BorrowFeeWorker) AccrueAll() {
+	positions, err := w.repo.GetOpenShorts()
+	if err != nil {
+		log.Printf("Error loading short positions: %v", err)
+		return
+	}
+	prices := map[string]float64{}
+	for i := range positions {
+		position := &positions[i]
+		if position.BorrowFeeRate == 0 {
+			continue
+		}
+		price, ok := prices[position.Symbol]
+		if !ok {
+			quote, err := FindQuote(position.Symbol)
+			if err != nil {
+				log.Printf("Error getting quote of short position %d: %v", position.ID, err)
+				continue
+			}
+			price = quote.RegularMarketPrice
+			prices[position.Symbol] = price
+		}
+		accruedAt := position.BorrowAccruedAt
+		if position.AccrueBorrowFee(price, time.Now()) == 0 {
+			continue
+		}
+		updated, err := w.repo.AccrueBorrowFees(position, accruedAt)
+		if err != nil {
+			log.Printf("Error accruing borrow fee of position %d: %v", position.ID, err)
+		} else if !updated {
+			log.Printf("Short position %d changed while accruing its borrow fee, it is accrued on the next run", position.ID)
+		}
+	}
+}
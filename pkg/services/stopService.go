@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/megajandrox/go-finance-api/pkg/calendar"
+	"github.com/megajandrox/go-finance-api/pkg/models"
+	"github.com/megajandrox/go-finance-api/pkg/repository"
+)
+
+const stopATRPeriod = 14
+
+// FindATRBySymbol calculates the daily ATR of the symbol
+func FindATRBySymbol(splits *SplitAdjuster, symbol string, period int) (float64, error) {
+	marketDataList, err := FindMarketDataBySymbol(splits, symbol, 3, "1d")
+	if err != nil {
+		return 0, err
+	}
+	atr, err := models.NewATR(symbol)
+	if err != nil {
+		return 0, err
+	}
+	return atr.CalculateLatestATR(marketDataList, period)
+}
+
+// SetPositionStop configures the stop of the position, fetching the ATR when it is needed
+func SetPositionStop(splits *SplitAdjuster, position *models.Position, stopType models.StopType, value float64) error {
+	var atr float64
+	if stopType == models.ATRTrailingStop {
+		var err error
+		atr, err = FindATRBySymbol(splits, position.Symbol, stopATRPeriod)
+		if err != nil {
+			return err
+		}
+	}
+	return position.SetStop(stopType, value, atr)
+}
+
+// StopWorker ratchets the stops of the open positions with the latest quotes
+type StopWorker struct {
+	repo     repository.PositionRepository
+	actions  repository.CorporateActionRepository
+	interval time.Duration
+}
+
+func NewStopWorker(repo repository.PositionRepository, actions repository.CorporateActionRepository, interval time.Duration) *StopWorker {
+	return &StopWorker{repo: repo, actions: actions, interval: interval}
+}
+
+// Start runs the updates in background until the context is cancelled
+func (w *StopWorker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			w.UpdateAll()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// UpdateAll updates the stop of every position with an active stop
+func (w *StopWorker) UpdateAll() {
+	positions, err := w.repo.GetWithActiveStop()
+	if err != nil {
+		log.Printf("Error loading positions: %v", err)
+		return
+	}
+	splits := NewSplitAdjuster(w.actions)
+	atrs := map[string]float64{}
+	for i := range positions {
+		w.updatePosition(&positions[i], splits, atrs)
+	}
+}
+
+func (w *StopWorker) updatePosition(position *models.Position, splits *SplitAdjuster, atrs map[string]float64) {
+	quote, err := FindQuote(position.Symbol)
+	if err != nil {
+		log.Printf("Error getting quote of position %d: %v", position.ID, err)
+		return
+	}
+	price := quote.RegularMarketPrice
+	// The high of the day only counts if the position was open before today
+	high := price
+	if position.EntryTime.Before(calendar.ForSymbol(position.Symbol).Date(time.Now())) {
+		high = quote.RegularMarketDayHigh
+	}
+	var atr float64
+	if position.StopType == models.ATRTrailingStop {
+		if cached, ok := atrs[position.Symbol]; ok {
+			atr = cached
+		} else if atr, err = FindATRBySymbol(splits, position.Symbol, stopATRPeriod); err == nil {
+			atrs[position.Symbol] = atr
+		} else {
+			log.Printf("Error calculating ATR of position %d, keeping the previous one: %v", position.ID, err)
+		}
+	}
+
+	previousHigh := position.HighestPrice
+	moved, breached := position.UpdateStop(price, high, atr)
+	if !moved && !breached && position.HighestPrice == previousHigh {
+		return
+	}
+	updated, err := w.repo.UpdateStop(position)
+	if err != nil {
+		log.Printf("Error updating stop of position %d: %v", position.ID, err)
+		return
+	}
+	if !updated {
+		log.Printf("Position %d changed while its stop was updated, the stop is updated on the next run", position.ID)
+		return
+	}
+	switch {
+	case breached:
+		log.Printf("Stop of position %d (%s) breached at %.2f, stop was %.2f", position.ID, position.Symbol, price, position.StopPrice)
+	case moved:
+		log.Printf("Stop of position %d (%s) moved to %.2f", position.ID, position.Symbol, position.StopPrice)
+	}
+}